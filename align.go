@@ -0,0 +1,70 @@
+package gocui
+
+// Alignment selects where lines are placed horizontally in a view.
+type Alignment int
+
+const (
+	AlignLeft Alignment = iota
+	AlignCenter
+	AlignRight
+)
+
+// lineAlignment returns the alignment of the view line with the given index.
+func (v *View) lineAlignment(y int) Alignment {
+	if v.LineAlignment != nil && y >= 0 && y < len(v.viewLines) {
+		return v.LineAlignment(v.viewLines[y].linesY)
+	}
+	return v.Alignment
+}
+
+// alignmentOffset returns the number of columns by which the view line with
+// the given index is shifted to the right to align it. Lines that don't fit
+// into the view are never shifted.
+func (v *View) alignmentOffset(y int) int {
+	alignment := v.lineAlignment(y)
+	if alignment == AlignLeft || y < 0 || y >= len(v.viewLines) {
+		return 0
+	}
+
+	width := 0
+	for _, c := range v.viewLines[y].line {
+		width += c.width
+	}
+	free := v.InnerWidth() - width
+	if free <= 0 {
+		return 0
+	}
+	if alignment == AlignCenter {
+		return free / 2
+	}
+	return free
+}
+
+// truncateLine cuts off the cells of a line that extend past the given
+// column, ending the line with an ellipsis. The ellipsis takes the colours of
+// the first cell that it replaces. contentXs, if not nil, holds the position
+// of each cell in the content, and is truncated along with the cells.
+func truncateLine(line []cell, contentXs []int, columns int) ([]cell, []int) {
+	width := 0
+	for _, c := range line {
+		width += c.width
+	}
+	if width <= columns || columns < 1 {
+		return line, contentXs
+	}
+
+	width = 0
+	for i, c := range line {
+		if width+c.width > columns-1 {
+			last := c
+			last.chr = ellipsis
+			last.width = 1
+			if contentXs != nil {
+				contentXs = append(contentXs[:i:i], contentXs[i])
+			}
+			return append(line[:i:i], last), contentXs
+		}
+		width += c.width
+	}
+	return line, contentXs
+}
@@ -0,0 +1,132 @@
+package gocui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncateLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		columns  int
+		expected string
+	}{
+		{name: "fits", line: "hello", columns: 5, expected: "hello"},
+		{name: "too long", line: "hello world", columns: 5, expected: "hell…"},
+		{name: "wide grapheme at the edge", line: "ab中文", columns: 4, expected: "ab…"},
+		{name: "wide graphemes", line: "中文字", columns: 5, expected: "中文…"},
+		{name: "no room", line: "hello", columns: 0, expected: "hello"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			line, _ := truncateLine(stringToCells(test.line), nil, test.columns)
+			assert.Equal(t, test.expected, cellsToString(line))
+		})
+	}
+}
+
+func TestDrawAlignment(t *testing.T) {
+	setupSimulationScreen(t, 20, 10)
+
+	v := NewView("v", 0, 0, 11, 5, OutputNormal)
+	v.Alignment = AlignRight
+	v.LineAlignment = func(lineIdx int) Alignment {
+		if lineIdx == 1 {
+			return AlignCenter
+		}
+		return v.Alignment
+	}
+	v.SetContent("12\nabcd\n中文\nmuch too long")
+
+	// wide graphemes are followed by a blank cell on the screen
+	v.draw()
+	assert.Equal(t, []string{
+		"        12",
+		"   abcd   ",
+		"      中 文 ",
+		"much too l",
+	}, []string{screenRow(1, 10, 1), screenRow(1, 10, 2), screenRow(1, 10, 3), screenRow(1, 10, 4)})
+
+	// the buffer is not affected
+	assert.Equal(t, "12\nabcd\n中文\nmuch too long", v.Buffer())
+}
+
+func TestDrawHighlightedAlignedLine(t *testing.T) {
+	setupSimulationScreen(t, 20, 10)
+
+	v := NewView("v", 0, 0, 11, 5, OutputNormal)
+	v.Alignment = AlignRight
+	v.Highlight = true
+	v.SelBgColor = ColorBlue
+	v.SetContent("12\nabcd")
+	v.draw()
+
+	for x := 1; x <= 10; x++ {
+		_, style, _ := Screen.Get(x, 1)
+		_, bg, _ := style.Decompose()
+		assert.Equal(t, tcell.ColorNavy, bg, "column %d", x)
+	}
+	_, style, _ := Screen.Get(1, 2)
+	_, bg, _ := style.Decompose()
+	assert.Equal(t, tcell.ColorDefault, bg)
+}
+
+func TestDrawTruncateWithEllipsis(t *testing.T) {
+	setupSimulationScreen(t, 20, 10)
+
+	v := NewView("v", 0, 0, 11, 5, OutputNormal)
+	v.TruncateWithEllipsis = true
+	v.SetContent("short\nmuch too \x1b[32mlong\x1b[0m\n一二三四五六")
+
+	v.draw()
+	assert.Equal(t, []string{
+		"short     ",
+		"much too …",
+		"一 二 三 四 … ",
+	}, []string{screenRow(1, 10, 1), screenRow(1, 10, 2), screenRow(1, 10, 3)})
+
+	// the ellipsis keeps the style of the text it replaces
+	_, style, _ := Screen.Get(10, 2)
+	fg, _, _ := style.Decompose()
+	assert.Equal(t, getTcellColor(ColorGreen, OutputNormal), fg)
+
+	v.SetOrigin(4, 0)
+	v.draw()
+	assert.Equal(t, " too long ", screenRow(1, 10, 2))
+	assert.Equal(t, "三 四 五 六   ", screenRow(1, 10, 3))
+
+	assert.Equal(t, "short\nmuch too long\n一二三四五六", v.Buffer())
+}
+
+func TestMouseCoordinatesWithAlignment(t *testing.T) {
+	g := &Gui{maxX: 40, maxY: 20}
+	v, _ := g.SetView("v", 0, 0, 11, 10, 0)
+	v.Alignment = AlignRight
+	v.SetContent("abc")
+	v.refreshViewLinesIfNeeded()
+
+	var clicked ViewMouseBindingOpts
+	assert.NoError(t, g.SetViewClickBinding(&ViewMouseBinding{
+		ViewName: "v",
+		Key:      MouseLeft,
+		Handler: func(opts ViewMouseBindingOpts) error {
+			clicked = opts
+			return nil
+		},
+	}))
+
+	// "abc" is drawn in the last three of the ten columns
+	assert.NoError(t, g.onKey(&GocuiEvent{Type: eventMouse, Key: MouseLeft, MouseX: 9, MouseY: 1}))
+	assert.Equal(t, 1, clicked.X)
+	assert.Equal(t, 1, v.CursorX())
+
+	// clicking on the padding goes to the start of the line
+	assert.NoError(t, g.onKey(&GocuiEvent{Type: eventMouse, Key: MouseLeft, MouseX: 2, MouseY: 1}))
+	assert.Equal(t, 0, clicked.X)
+
+	assert.Equal(t, 9, v.logicalToVisualX(2, 0))
+}
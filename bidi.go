@@ -0,0 +1,643 @@
+package gocui
+
+import (
+	"slices"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/bidi"
+)
+
+// bidiMirrors maps characters that are mirrored in right-to-left text, but
+// aren't brackets, to the characters that are shown in their place. The bidi
+// package only has mirroring data for brackets.
+var bidiMirrors = map[rune]rune{
+	'<': '>', '>': '<',
+	'«': '»', '»': '«',
+	'‹': '›', '›': '‹',
+	'≤': '≥', '≥': '≤',
+	'≦': '≧', '≧': '≦',
+	'≪': '≫', '≫': '≪',
+	'≺': '≻', '≻': '≺',
+	'⊂': '⊃', '⊃': '⊂',
+	'⊆': '⊇', '⊇': '⊆',
+	'∈': '∋', '∋': '∈',
+}
+
+// bidiVisualOrder returns the visual order of the cells of a line, i.e. the
+// index of the cell that is shown at each position, and the embedding level
+// of each cell (odd levels are right-to-left), according to the Unicode
+// bidirectional algorithm (UAX #9). If rtl is true, the base direction is
+// right-to-left. Returns nil if the line doesn't need reordering.
+func bidiVisualOrder(line []cell, rtl bool) ([]int, []int) {
+	if !rtl && !slices.ContainsFunc(line, func(c cell) bool { return mayBeRightToLeft(c.chr) }) {
+		return nil, nil
+	}
+
+	baseLevel := 0
+	if rtl {
+		baseLevel = 1
+	}
+
+	var runes []rune
+	var runeCells []int
+	for i, c := range line {
+		for _, r := range c.chr {
+			runes = append(runes, r)
+			runeCells = append(runeCells, i)
+		}
+	}
+	runeLevels := bidiLevels(runes, baseLevel)
+
+	// the level of a cell is the level of its first rune
+	levels := make([]int, len(line))
+	for i := range levels {
+		levels[i] = baseLevel
+	}
+	for i := len(runeCells) - 1; i >= 0; i-- {
+		levels[runeCells[i]] = runeLevels[i]
+	}
+
+	// L2: reverse runs, from the highest level down to the lowest odd level
+	order := make([]int, len(line))
+	for i := range order {
+		order[i] = i
+	}
+	maxLevel := baseLevel
+	for _, level := range levels {
+		maxLevel = max(maxLevel, level)
+	}
+	for level := maxLevel; level >= 1; level-- {
+		for i := 0; i < len(order); i++ {
+			if levels[order[i]] < level {
+				continue
+			}
+			end := i
+			for end < len(order) && levels[order[end]] >= level {
+				end++
+			}
+			slices.Reverse(order[i:end])
+			i = end
+		}
+	}
+
+	return order, levels
+}
+
+// bidiSequence is an isolating run sequence: the runes (by index) that the
+// rules for weak and neutral types treat as one unit.
+type bidiSequence struct {
+	runes []int
+	level int
+	// sos and eos are the directions at the start and the end of the
+	// sequence, as L or R
+	sos bidi.Class
+	eos bidi.Class
+}
+
+// bidiLevels returns the embedding level of each rune of a line, given the
+// level of its paragraph. The bidi package has the character data that we
+// need, but we can't use its Paragraph: it never pairs brackets, as it
+// compares the closing bracket with the opening one, so it gets the
+// direction of text in and around brackets wrong (rule N0).
+func bidiLevels(runes []rune, baseLevel int) []int {
+	classes := make([]bidi.Class, len(runes))
+	for i, r := range runes {
+		props, _ := bidi.LookupRune(r)
+		classes[i] = props.Class()
+	}
+
+	embeddingLevels, sequences, resolved := bidiExplicitLevels(runes, classes, baseLevel)
+	for _, seq := range sequences {
+		resolveBidiWeakTypes(resolved, seq)
+		resolveBidiBrackets(runes, classes, resolved, seq)
+		resolveBidiNeutrals(resolved, seq)
+	}
+
+	// I1 and I2: text against the embedding direction goes one level up,
+	// numbers in left-to-right text two levels
+	levels := slices.Clone(embeddingLevels)
+	for _, seq := range sequences {
+		for _, i := range seq.runes {
+			switch {
+			case seq.level%2 == 0 && resolved[i] == bidi.R:
+				levels[i]++
+			case seq.level%2 == 0 && (resolved[i] == bidi.EN || resolved[i] == bidi.AN):
+				levels[i] += 2
+			case seq.level%2 == 1 && resolved[i] != bidi.R:
+				levels[i]++
+			}
+		}
+	}
+
+	// X9: the characters that the rules ignore take the level of the one
+	// before them, so that they don't split the runs that are reversed
+	for i, class := range classes {
+		if isRemovedBidiClass(class) && i > 0 {
+			levels[i] = levels[i-1]
+		}
+	}
+
+	// L1: segment separators, and whitespace before them and at the end of
+	// the line, get the base level
+	isWhitespace := func(class bidi.Class) bool {
+		switch class {
+		case bidi.WS, bidi.BN, bidi.LRE, bidi.RLE, bidi.LRO, bidi.RLO, bidi.PDF, bidi.LRI, bidi.RLI, bidi.FSI, bidi.PDI:
+			return true
+		}
+		return false
+	}
+	for i := len(classes) - 1; i >= 0; i-- {
+		if classes[i] != bidi.S && classes[i] != bidi.B && (i < len(classes)-1 || !isWhitespace(classes[i])) {
+			continue
+		}
+		levels[i] = baseLevel
+		for j := i - 1; j >= 0 && isWhitespace(classes[j]); j-- {
+			levels[j] = baseLevel
+		}
+	}
+
+	return levels
+}
+
+// isRemovedBidiClass returns true for the classes that rule X9 removes from
+// the text, i.e. the ones that the later rules ignore.
+func isRemovedBidiClass(class bidi.Class) bool {
+	switch class {
+	case bidi.BN, bidi.LRE, bidi.RLE, bidi.LRO, bidi.RLO, bidi.PDF:
+		return true
+	}
+	return false
+}
+
+// bidiExplicitLevels applies the rules for explicit embeddings, overrides and
+// isolates (X1-X10). It returns the embedding level of each rune, the
+// isolating run sequences, and the classes of the runes with the overrides
+// applied.
+func bidiExplicitLevels(runes []rune, classes []bidi.Class, baseLevel int) ([]int, []*bidiSequence, []bidi.Class) {
+	nextLevel := func(level int, odd bool) int {
+		if (level%2 == 1) == odd {
+			return level + 2
+		}
+		return level + 1
+	}
+
+	// override is L or R inside a directional override, and ON otherwise;
+	// initiator is the index of the rune that started an isolate
+	type embedding struct {
+		level     int
+		override  bidi.Class
+		initiator int
+	}
+	stack := []embedding{{level: baseLevel, override: bidi.ON, initiator: -1}}
+	isolates := 0
+	// the index of the PDI that closes each isolate initiator
+	closingPDIs := map[int]int{}
+	embeddingLevels := make([]int, len(runes))
+	resolved := slices.Clone(classes)
+	for i, class := range classes {
+		top := stack[len(stack)-1]
+		embeddingLevels[i] = top.level
+		if !isRemovedBidiClass(class) && top.override != bidi.ON {
+			resolved[i] = top.override
+		}
+
+		switch class {
+		case bidi.LRE, bidi.LRO, bidi.LRI, bidi.RLE, bidi.RLO, bidi.RLI, bidi.FSI:
+			odd := class == bidi.RLE || class == bidi.RLO || class == bidi.RLI
+			if class == bidi.FSI {
+				odd = firstStrongIsRightToLeft(runes[i+1:], true)
+			}
+			initiator := -1
+			if class == bidi.LRI || class == bidi.RLI || class == bidi.FSI {
+				initiator = i
+				isolates++
+			}
+			override := bidi.ON
+			switch class {
+			case bidi.LRO:
+				override = bidi.L
+			case bidi.RLO:
+				override = bidi.R
+			}
+			stack = append(stack, embedding{level: nextLevel(top.level, odd), override: override, initiator: initiator})
+		case bidi.PDF:
+			if len(stack) > 1 && top.initiator < 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case bidi.PDI:
+			// a PDI without an isolate to close is neutral
+			for isolates > 0 {
+				popped := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				if popped.initiator >= 0 {
+					closingPDIs[popped.initiator] = i
+					isolates--
+					break
+				}
+			}
+			top = stack[len(stack)-1]
+			embeddingLevels[i] = top.level
+			resolved[i] = class
+			if top.override != bidi.ON {
+				resolved[i] = top.override
+			}
+		}
+	}
+
+	// X10: split the text, without the characters that X9 removes, into runs
+	// of the same level, and chain the runs before and after each isolate
+	var levelRuns [][]int
+	for i, class := range classes {
+		if isRemovedBidiClass(class) {
+			continue
+		}
+		if n := len(levelRuns); n > 0 && embeddingLevels[levelRuns[n-1][0]] == embeddingLevels[i] {
+			levelRuns[n-1] = append(levelRuns[n-1], i)
+		} else {
+			levelRuns = append(levelRuns, []int{i})
+		}
+	}
+	runStarts := map[int][]int{}
+	for _, run := range levelRuns {
+		runStarts[run[0]] = run
+	}
+	closing := map[int]bool{}
+	for _, pdi := range closingPDIs {
+		closing[pdi] = true
+	}
+
+	var sequences []*bidiSequence
+	for _, run := range levelRuns {
+		if closing[run[0]] {
+			continue
+		}
+		seq := &bidiSequence{level: embeddingLevels[run[0]]}
+		for {
+			seq.runes = append(seq.runes, run...)
+			pdi, ok := closingPDIs[run[len(run)-1]]
+			if !ok {
+				break
+			}
+			run = runStarts[pdi]
+		}
+		sequences = append(sequences, seq)
+	}
+
+	// the direction at either end of a sequence is the one of the higher of
+	// its level and the level of the text next to it
+	direction := func(level int) bidi.Class {
+		if level%2 == 1 {
+			return bidi.R
+		}
+		return bidi.L
+	}
+	levelAround := func(i, step int) int {
+		for i += step; i >= 0 && i < len(runes); i += step {
+			if !isRemovedBidiClass(classes[i]) {
+				return embeddingLevels[i]
+			}
+		}
+		return baseLevel
+	}
+	for _, seq := range sequences {
+		first, last := seq.runes[0], seq.runes[len(seq.runes)-1]
+		seq.sos = direction(max(seq.level, levelAround(first, -1)))
+		switch classes[last] {
+		case bidi.LRI, bidi.RLI, bidi.FSI:
+			seq.eos = direction(max(seq.level, baseLevel))
+		default:
+			seq.eos = direction(max(seq.level, levelAround(last, 1)))
+		}
+	}
+
+	return embeddingLevels, sequences, resolved
+}
+
+// resolveBidiWeakTypes applies the rules for weak types (W1-W7) to the classes
+// of the runes of an isolating run sequence, so that the numbers in it end up
+// as EN or AN.
+func resolveBidiWeakTypes(classes []bidi.Class, seq *bidiSequence) {
+	// W1: marks take the class of the character before them
+	prev := seq.sos
+	for _, i := range seq.runes {
+		if classes[i] == bidi.NSM {
+			classes[i] = prev
+			if prev == bidi.LRI || prev == bidi.RLI || prev == bidi.FSI || prev == bidi.PDI {
+				classes[i] = bidi.ON
+			}
+		}
+		prev = classes[i]
+	}
+
+	// W2 and W3: numbers after Arabic letters are Arabic numbers, and Arabic
+	// letters are right-to-left
+	lastStrong := seq.sos
+	for _, i := range seq.runes {
+		switch classes[i] {
+		case bidi.L, bidi.R:
+			lastStrong = classes[i]
+		case bidi.AL:
+			lastStrong = bidi.AL
+			classes[i] = bidi.R
+		case bidi.EN:
+			if lastStrong == bidi.AL {
+				classes[i] = bidi.AN
+			}
+		}
+	}
+
+	// W4: a single separator between two numbers of the same kind joins them
+	for j := 1; j+1 < len(seq.runes); j++ {
+		before, class, after := classes[seq.runes[j-1]], classes[seq.runes[j]], classes[seq.runes[j+1]]
+		if before == after && (before == bidi.EN && (class == bidi.ES || class == bidi.CS) || before == bidi.AN && class == bidi.CS) {
+			classes[seq.runes[j]] = before
+		}
+	}
+
+	// W5: terminators next to European numbers belong to them
+	for j := 0; j < len(seq.runes); j++ {
+		if classes[seq.runes[j]] != bidi.ET {
+			continue
+		}
+		end := j
+		for end < len(seq.runes) && classes[seq.runes[end]] == bidi.ET {
+			end++
+		}
+		if (j > 0 && classes[seq.runes[j-1]] == bidi.EN) || (end < len(seq.runes) && classes[seq.runes[end]] == bidi.EN) {
+			for k := j; k < end; k++ {
+				classes[seq.runes[k]] = bidi.EN
+			}
+		}
+		j = end
+	}
+
+	// W6 and W7: the remaining separators and terminators are neutral, and
+	// European numbers in left-to-right text are left-to-right
+	lastStrong = seq.sos
+	for _, i := range seq.runes {
+		switch classes[i] {
+		case bidi.ES, bidi.ET, bidi.CS:
+			classes[i] = bidi.ON
+		case bidi.L, bidi.R:
+			lastStrong = classes[i]
+		case bidi.EN:
+			if lastStrong == bidi.L {
+				classes[i] = bidi.L
+			}
+		}
+	}
+}
+
+// strongBidiDirection returns the direction that a resolved class counts as
+// for the rules for neutral types, or ON if it has none.
+func strongBidiDirection(class bidi.Class) bidi.Class {
+	switch class {
+	case bidi.L:
+		return bidi.L
+	case bidi.R, bidi.EN, bidi.AN:
+		return bidi.R
+	}
+	return bidi.ON
+}
+
+// resolveBidiBrackets applies rule N0 to the bracket pairs of an isolating
+// run sequence: a pair takes the direction of the text inside it, preferring
+// the embedding direction.
+func resolveBidiBrackets(runes []rune, originalClasses, classes []bidi.Class, seq *bidiSequence) {
+	// BD16: pair up the brackets, giving up after 63 levels of nesting
+	type bracketPair struct{ opening, closing int }
+	var pairs []bracketPair
+	var openers []int
+	for j, i := range seq.runes {
+		if classes[i] != bidi.ON {
+			continue
+		}
+		props, _ := bidi.LookupRune(runes[i])
+		if props.IsOpeningBracket() {
+			if len(openers) == 63 {
+				break
+			}
+			openers = append(openers, j)
+		} else if props.IsBracket() {
+			for k := len(openers) - 1; k >= 0; k-- {
+				if bidi.ReverseString(string(runes[seq.runes[openers[k]]])) == string(runes[i]) {
+					pairs = append(pairs, bracketPair{openers[k], j})
+					openers = openers[:k]
+					break
+				}
+			}
+		}
+	}
+	slices.SortFunc(pairs, func(a, b bracketPair) int { return a.opening - b.opening })
+
+	embeddingDirection := bidi.L
+	if seq.level%2 == 1 {
+		embeddingDirection = bidi.R
+	}
+	for _, pair := range pairs {
+		direction := bidi.ON
+		for j := pair.opening + 1; j < pair.closing; j++ {
+			switch strongBidiDirection(classes[seq.runes[j]]) {
+			case embeddingDirection:
+				direction = embeddingDirection
+			case bidi.ON:
+			default:
+				if direction == bidi.ON {
+					direction = strongBidiDirection(classes[seq.runes[j]])
+				}
+			}
+		}
+		if direction == bidi.ON {
+			continue
+		}
+		if direction != embeddingDirection {
+			// the text inside is only against the embedding direction, so the
+			// pair goes with it if the text before it does too
+			context := seq.sos
+			for j := pair.opening - 1; j >= 0; j-- {
+				if strong := strongBidiDirection(classes[seq.runes[j]]); strong != bidi.ON {
+					context = strong
+					break
+				}
+			}
+			if context != direction {
+				direction = embeddingDirection
+			}
+		}
+
+		for _, j := range []int{pair.opening, pair.closing} {
+			classes[seq.runes[j]] = direction
+			// marks after a bracket go with it
+			for k := j + 1; k < len(seq.runes) && originalClasses[seq.runes[k]] == bidi.NSM; k++ {
+				classes[seq.runes[k]] = direction
+			}
+		}
+	}
+}
+
+// resolveBidiNeutrals applies rules N1 and N2 to an isolating run sequence:
+// neutral text between text of the same direction takes that direction, and
+// otherwise the embedding direction.
+func resolveBidiNeutrals(classes []bidi.Class, seq *bidiSequence) {
+	embeddingDirection := bidi.L
+	if seq.level%2 == 1 {
+		embeddingDirection = bidi.R
+	}
+	for j := 0; j < len(seq.runes); j++ {
+		if strongBidiDirection(classes[seq.runes[j]]) != bidi.ON {
+			continue
+		}
+		end := j
+		for end < len(seq.runes) && strongBidiDirection(classes[seq.runes[end]]) == bidi.ON {
+			end++
+		}
+		before, after := seq.sos, seq.eos
+		if j > 0 {
+			before = strongBidiDirection(classes[seq.runes[j-1]])
+		}
+		if end < len(seq.runes) {
+			after = strongBidiDirection(classes[seq.runes[end]])
+		}
+		direction := embeddingDirection
+		if before == after {
+			direction = before
+		}
+		for k := j; k < end; k++ {
+			classes[seq.runes[k]] = direction
+		}
+		j = end
+	}
+}
+
+// bidiMirror returns the character that is shown in place of the given one
+// in right-to-left text.
+func bidiMirror(chr string) string {
+	r, size := utf8.DecodeRuneInString(chr)
+	if props, _ := bidi.LookupRune(r); props.IsBracket() {
+		return bidi.ReverseString(string(r)) + chr[size:]
+	}
+	if mirror, ok := bidiMirrors[r]; ok {
+		return string(mirror) + chr[size:]
+	}
+	return chr
+}
+
+// mayBeRightToLeft returns true if the string starts with a character that
+// could make a line need reordering: a right-to-left letter, or an explicit
+// formatting character that starts right-to-left text.
+func mayBeRightToLeft(chr string) bool {
+	for _, r := range chr {
+		props, _ := bidi.LookupRune(r)
+		switch props.Class() {
+		case bidi.R, bidi.AL, bidi.RLE, bidi.RLO, bidi.RLI, bidi.FSI:
+			return true
+		}
+		return false
+	}
+	return false
+}
+
+// isRightToLeftParagraph returns true if the first strong character of the
+// line is right-to-left (rules P2 and P3 of UAX #9).
+func isRightToLeftParagraph(line []cell) bool {
+	var runes []rune
+	for _, c := range line {
+		runes = append(runes, []rune(c.chr)...)
+	}
+	return firstStrongIsRightToLeft(runes, false)
+}
+
+// firstStrongIsRightToLeft returns true if the first strong character of the
+// text is right-to-left. Isolates are skipped, and if inIsolate is true, the
+// end of the isolate that the text is in ends the search.
+func firstStrongIsRightToLeft(runes []rune, inIsolate bool) bool {
+	depth := 0
+	for _, r := range runes {
+		props, _ := bidi.LookupRune(r)
+		switch props.Class() {
+		case bidi.L, bidi.R, bidi.AL:
+			if depth == 0 {
+				return props.Class() != bidi.L
+			}
+		case bidi.LRI, bidi.RLI, bidi.FSI:
+			depth++
+		case bidi.PDI:
+			if depth == 0 && inIsolate {
+				return false
+			}
+			depth = max(depth-1, 0)
+		}
+	}
+	return false
+}
+
+// bidiLine returns the cells of the view line with the given index in visual
+// order, and the logical x position of each of them. Returns nil if the view
+// doesn't reorder bidirectional text, or if the line doesn't need it.
+func (v *View) bidiLine(y int) ([]cell, []int) {
+	if !v.BidiReordering || y < 0 || y >= len(v.viewLines) {
+		return nil, nil
+	}
+
+	vline := v.viewLines[y]
+	rtl := false
+	if vline.linesY < len(v.lines) {
+		rtl = isRightToLeftParagraph(v.lines[vline.linesY])
+	}
+	order, levels := bidiVisualOrder(vline.line, rtl)
+	if order == nil {
+		return nil, nil
+	}
+
+	logicalX := make([]int, len(vline.line))
+	x := 0
+	for i, c := range vline.line {
+		logicalX[i] = x
+		x += c.width
+	}
+
+	cells := make([]cell, len(order))
+	xs := make([]int, len(order))
+	for i, idx := range order {
+		cells[i] = vline.line[idx]
+		xs[i] = logicalX[idx]
+		if levels[idx]%2 == 1 {
+			cells[i].chr = bidiMirror(cells[i].chr)
+		}
+	}
+	return cells, xs
+}
+
+// visualToLogicalX maps a column of the view line with the given index, as it
+// is shown on the screen, to the column in the line's content, taking into
+// account the line's alignment and bidi reordering.
+func (v *View) visualToLogicalX(x, y int) int {
+	if offset := v.alignmentOffset(y); offset > 0 {
+		x = max(x-offset, 0)
+	}
+
+	cells, xs := v.bidiLine(y)
+	visualX := 0
+	for i, c := range cells {
+		if x >= visualX && x < visualX+max(c.width, 1) {
+			return xs[i]
+		}
+		visualX += c.width
+	}
+	return x
+}
+
+// logicalToVisualX maps a column in the content of the view line with the
+// given index to the column where it is shown on the screen.
+func (v *View) logicalToVisualX(x, y int) int {
+	offset := v.alignmentOffset(y)
+	cells, xs := v.bidiLine(y)
+	visualX := 0
+	for i, c := range cells {
+		if xs[i] == x {
+			return visualX + offset
+		}
+		visualX += c.width
+	}
+	return x + offset
+}
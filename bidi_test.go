@@ -0,0 +1,215 @@
+package gocui
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/unicode/bidi"
+)
+
+func TestBidiVisualOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		rtl      bool
+		expected string
+	}{
+		{name: "left-to-right only", line: "abc def", expected: "abc def"},
+		{name: "right-to-left word", line: "abc אבג def", expected: "abc גבא def"},
+		{name: "right-to-left words", line: "abc אבג דהו", expected: "abc והד גבא"},
+		{name: "numbers in right-to-left paragraph", line: "אבג 123", rtl: true, expected: "123 גבא"},
+		{name: "trailing whitespace", line: "אבג  ", rtl: true, expected: "  גבא"},
+		{name: "arabic", line: "fix: إصلاح", expected: "fix: حالصإ"},
+		{name: "numbers after right-to-left text", line: "abc אבג 123", expected: "abc 123 גבא"},
+		{name: "numbers in left-to-right text", line: "אבג 123 abc", expected: "123 גבא abc"},
+		{name: "embedding", line: "x \u202bאב cd\u202c y", expected: "x cd בא y"},
+		{name: "nested embeddings", line: "x \u202bאב \u202acd ef\u202c גד\u202c y", expected: "x דג cd ef בא y"},
+		{name: "isolate", line: "x \u2067cd אב\u2069 y", expected: "x בא cd y"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			line := stringToCells(test.line)
+			order, _ := bidiVisualOrder(line, test.rtl)
+			if order == nil {
+				assert.Equal(t, test.expected, test.line)
+				return
+			}
+
+			var b strings.Builder
+			for _, idx := range order {
+				b.WriteString(line[idx].chr)
+			}
+			// explicit formatting characters aren't shown
+			visual := strings.Map(func(r rune) rune {
+				if unicode.Is(unicode.Bidi_Control, r) {
+					return -1
+				}
+				return r
+			}, b.String())
+			assert.Equal(t, test.expected, visual)
+		})
+	}
+}
+
+func TestBidiLevels(t *testing.T) {
+	// cases in the format of BidiCharacterTest.txt from the UCD: the text, the
+	// paragraph direction (0 is left-to-right, 1 right-to-left, 2 auto), the
+	// resolved levels (x for the characters that X9 removes), and the visual
+	// order without the removed characters
+	tests := []struct {
+		text      string
+		paragraph int
+		levels    string
+		order     string
+	}{
+		{"\u05d0\u05d1 12 \u05d2", 0, "1 1 1 2 2 1 1", "6 5 3 4 2 1 0"},
+		{"a $10 \u05d0", 0, "0 0 0 0 0 0 1", "0 1 2 3 4 5 6"},
+		{"\u0627 12%", 0, "1 1 2 2 0", "2 3 1 0 4"},
+		{"\u0628 \u0661,\u0662 c", 0, "1 1 2 2 2 0 0", "2 3 4 1 0 5 6"},
+		{"$\u0661 \u05d0", 0, "0 2 1 1", "0 3 2 1"},
+		{"a (\u05d0) b", 0, "0 0 0 1 0 0 0", "0 1 2 3 4 5 6"},
+		{"\u05d0 (a) \u05d1", 0, "1 0 0 0 0 0 1", "0 1 2 3 4 5 6"},
+		{"a(\u05d0)!", 0, "0 0 1 0 0", "0 1 2 3 4"},
+		{"\u05d0 [1)] b", 0, "1 1 1 2 1 1 0 0", "5 4 3 2 1 0 6 7"},
+		{"a (b).", 1, "2 2 2 2 2 1", "5 0 1 2 3 4"},
+		{"ab !!", 1, "2 2 1 1 1", "4 3 2 0 1"},
+		{"a \u202b\u05d0 b\u202c c", 0, "0 0 x 1 1 2 x 0 0", "0 1 5 4 3 7 8"},
+		{"a \u202ebc 12\u202c d", 0, "0 0 x 1 1 1 1 1 x 0 0", "0 1 7 6 5 4 3 9 10"},
+		{"\u05d0 \u202d\u05d1\u05d2\u202c 1", 1, "1 1 x 2 2 x 2 2", "3 4 6 7 1 0"},
+		{"a \u2067b \u05d0\u2069 c", 0, "0 0 0 2 1 1 0 0 0", "0 1 2 5 4 3 6 7 8"},
+		{"\u05d0\u2066a 1\u2069\u05d1", 0, "1 1 2 2 2 1 1", "6 5 2 3 4 1 0"},
+		{"a \u2068\u05d0 b\u2069 c", 0, "0 0 0 1 1 2 0 0 0", "0 1 2 5 4 3 6 7 8"},
+		{"a\u2069 \u05d0", 1, "2 1 1 1", "3 2 1 0"},
+		{"\u05d0 \u200b1 \u05d1", 0, "1 1 x 2 1 1", "5 4 3 1 0"},
+		{"\u05d0\u0300 a\u0300", 0, "1 1 0 0 0", "1 0 2 3 4"},
+		{"1.2 \u05d0 3+4", 0, "0 0 0 0 1 1 2 2 2", "0 1 2 3 6 7 8 5 4"},
+		{"\u06271,2 d", 0, "1 2 2 2 0 0", "1 2 3 0 4 5"},
+		{" \u05d0 a", 2, "1 1 1 2", "3 2 1 0"},
+		{"\u2067a\u2069 b", 2, "0 2 0 0 0", "0 1 2 3 4"},
+		{"a\u0009\u05d0 \u0009\u05d1", 0, "0 0 1 0 0 1", "0 1 2 3 4 5"},
+		{"a  \u0009\u05d0  ", 1, "2 1 1 1 1 1 1", "6 5 4 3 2 1 0"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			var line []cell
+			for _, r := range test.text {
+				line = append(line, cell{chr: string(r), width: 1})
+			}
+			rtl := test.paragraph == 1 || test.paragraph == 2 && isRightToLeftParagraph(line)
+			order, levels := bidiVisualOrder(line, rtl)
+			if order == nil {
+				order, levels = make([]int, len(line)), make([]int, len(line))
+				for i := range order {
+					order[i] = i
+				}
+			}
+
+			removed := func(i int) bool {
+				props, _ := bidi.LookupRune([]rune(line[i].chr)[0])
+				return isRemovedBidiClass(props.Class())
+			}
+			var gotLevels, gotOrder []string
+			for i, level := range levels {
+				if removed(i) {
+					gotLevels = append(gotLevels, "x")
+				} else {
+					gotLevels = append(gotLevels, strconv.Itoa(level))
+				}
+			}
+			for _, i := range order {
+				if !removed(i) {
+					gotOrder = append(gotOrder, strconv.Itoa(i))
+				}
+			}
+			assert.Equal(t, test.levels, strings.Join(gotLevels, " "))
+			assert.Equal(t, test.order, strings.Join(gotOrder, " "))
+		})
+	}
+}
+
+func TestMayBeRightToLeft(t *testing.T) {
+	assert.True(t, mayBeRightToLeft("א"))
+	assert.True(t, mayBeRightToLeft("ب"))
+	assert.True(t, mayBeRightToLeft("\u202e"))
+	assert.False(t, mayBeRightToLeft("a"))
+	assert.False(t, mayBeRightToLeft("漢"))
+	assert.False(t, mayBeRightToLeft("😀"))
+	assert.False(t, mayBeRightToLeft("١"))
+}
+
+func TestBidiReorderingSnapshot(t *testing.T) {
+	setupSimulationScreen(t, 20, 5)
+	g := &Gui{maxX: 20, maxY: 5, screen: Screen}
+
+	v, _ := g.SetView("v", 0, 0, 19, 4, 0)
+	v.Frame = false
+	v.SetContent("abc אבג\nשלום (world)")
+
+	v.draw()
+	lines := strings.Split(g.Snapshot(), "\n")
+	assert.Equal(t, "abc אבג", strings.TrimSpace(lines[1]))
+
+	v.BidiReordering = true
+	v.draw()
+	lines = strings.Split(g.Snapshot(), "\n")
+	assert.Equal(t, "abc גבא", strings.TrimSpace(lines[1]))
+	assert.Equal(t, "(world) םולש", strings.TrimSpace(lines[2]))
+}
+
+func TestBidiMirroring(t *testing.T) {
+	v := NewView("v", 0, 0, 40, 5, OutputNormal)
+	v.BidiReordering = true
+	v.SetContent("אב (ג) «ד» ⟨ה⟩ ו≤ז")
+	v.refreshViewLinesIfNeeded()
+
+	cells, _ := v.bidiLine(0)
+	assert.Equal(t, "ז≥ו ⟨ה⟩ «ד» (ג) בא", cellsToString(cells))
+}
+
+func TestBidiPositionMapping(t *testing.T) {
+	v := NewView("v", 0, 0, 20, 5, OutputNormal)
+	v.BidiReordering = true
+	v.SetContent("abc אבג")
+	v.refreshViewLinesIfNeeded()
+
+	// visually, the line reads "abc גבא"
+	assert.Equal(t, 6, v.visualToLogicalX(4, 0))
+	assert.Equal(t, 4, v.visualToLogicalX(6, 0))
+	assert.Equal(t, 1, v.visualToLogicalX(1, 0))
+	assert.Equal(t, 4, v.logicalToVisualX(6, 0))
+	assert.Equal(t, 6, v.logicalToVisualX(4, 0))
+
+	// positions past the end of the line are unaffected
+	assert.Equal(t, 10, v.visualToLogicalX(10, 0))
+
+	v.BidiReordering = false
+	assert.Equal(t, 4, v.visualToLogicalX(4, 0))
+}
+
+func TestBidiMouseClick(t *testing.T) {
+	g := &Gui{maxX: 40, maxY: 20}
+	v, _ := g.SetView("v", 0, 0, 20, 10, 0)
+	v.BidiReordering = true
+	v.SetContent("abc אבג")
+	v.refreshViewLinesIfNeeded()
+
+	var clicked ViewMouseBindingOpts
+	assert.NoError(t, g.SetViewClickBinding(&ViewMouseBinding{
+		ViewName: "v",
+		Key:      MouseLeft,
+		Handler: func(opts ViewMouseBindingOpts) error {
+			clicked = opts
+			return nil
+		},
+	}))
+
+	// clicking on the leftmost Hebrew letter, which is the last one logically
+	assert.NoError(t, g.onKey(&GocuiEvent{Type: eventMouse, Key: MouseLeft, MouseX: 5, MouseY: 1}))
+	assert.Equal(t, 6, clicked.X)
+	assert.Equal(t, 6, v.CursorX())
+}
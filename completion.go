@@ -0,0 +1,152 @@
+package gocui
+
+import (
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/rivo/uniseg"
+)
+
+// CompletionViewName is the name of the view that shows completion candidates.
+const CompletionViewName = "completion"
+
+// maxCompletionHeight is the maximum number of candidates that are visible in
+// the completion dropdown at once.
+const maxCompletionHeight = 10
+
+// CompletionProvider returns the completion candidates for the given content
+// of a text area and cursor position (a byte offset into the content).
+type CompletionProvider func(content string, cursor int) []string
+
+// completion is the state of an open completion dropdown.
+type completion struct {
+	// the editable view that the candidates are for
+	view       *View
+	dropdown   *View
+	candidates []string
+	selected   int
+}
+
+// updateCompletion asks the completion provider of the given view for
+// candidates and shows them in a dropdown below the cursor, or hides the
+// dropdown if there are none.
+func (g *Gui) updateCompletion(v *View) error {
+	if v == nil || !v.Editable || v.CompletionProvider == nil {
+		return nil
+	}
+
+	candidates := v.CompletionProvider(v.TextArea.GetUnwrappedContent(), v.TextArea.GetCursor())
+	if len(candidates) == 0 {
+		g.hideCompletion()
+		return nil
+	}
+
+	return g.showCompletion(v, candidates)
+}
+
+func (g *Gui) showCompletion(v *View, candidates []string) error {
+	contentWidth := 0
+	for _, candidate := range candidates {
+		contentWidth = max(contentWidth, uniseg.StringWidth(candidate))
+	}
+	width := min(contentWidth+4, g.maxX)
+	height := min(len(candidates), maxCompletionHeight) + 2
+
+	// anchor the dropdown at the cursor, so that the candidates start in the
+	// cursor's column
+	cursorX, cursorY := v.TextArea.GetCursorXY()
+	screenX := v.contentX0() + cursorX - v.ox
+	screenY := v.contentY0() + cursorY - v.oy
+	x0 := max(0, min(screenX-2, g.maxX-width))
+	y0 := screenY + 1
+	if y0+height > g.maxY && screenY-height >= 0 {
+		y0 = screenY - height
+	}
+
+	dropdown, err := g.SetView(CompletionViewName, x0, y0, x0+width-1, y0+height-1, 0)
+	if err != nil && !errors.Is(err, ErrUnknownView) {
+		return err
+	}
+
+	isNew := g.completion == nil
+	g.completion = &completion{view: v, dropdown: dropdown, candidates: candidates}
+
+	lines := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		lines[i] = " " + candidate + " "
+	}
+	dropdown.Highlight = true
+	dropdown.SetContent(strings.Join(lines, "\n"))
+	dropdown.SetOrigin(0, 0)
+	g.selectCompletion(0)
+
+	if isNew {
+		if err := g.SetViewClickBinding(&ViewMouseBinding{
+			ViewName: CompletionViewName,
+			Key:      MouseLeft,
+			Handler: func(opts ViewMouseBindingOpts) error {
+				if g.completion == nil || opts.Y < 0 || opts.Y >= len(g.completion.candidates) {
+					return nil
+				}
+				g.selectCompletion(opts.Y)
+				return g.acceptCompletion()
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	_, err = g.SetViewOnTop(CompletionViewName)
+	return err
+}
+
+func (g *Gui) selectCompletion(index int) {
+	c := g.completion
+	c.selected = (index + len(c.candidates)) % len(c.candidates)
+	c.dropdown.FocusPoint(0, c.selected, true)
+}
+
+// acceptCompletion replaces the token at the cursor with the selected
+// candidate and hides the dropdown.
+func (g *Gui) acceptCompletion() error {
+	c := g.completion
+	g.hideCompletion()
+
+	c.view.TextArea.ReplaceCurrentToken(c.candidates[c.selected])
+	c.view.RenderTextArea()
+	return nil
+}
+
+func (g *Gui) hideCompletion() {
+	if g.completion == nil {
+		return
+	}
+
+	g.completion = nil
+	g.viewMouseBindings = deleteViewMouseBindings(g.viewMouseBindings, CompletionViewName)
+	_ = g.DeleteView(CompletionViewName)
+}
+
+// execCompletionKeybindings handles the keys for navigating the completion
+// dropdown while it's open for the given view. Returns true if the key was
+// handled.
+func (g *Gui) execCompletionKeybindings(v *View, ev *GocuiEvent) (bool, error) {
+	if g.completion == nil || g.completion.view != v || ev.Ch != 0 || ev.Mod != ModNone {
+		return false, nil
+	}
+
+	switch ev.Key {
+	case KeyArrowDown, KeyCtrlN:
+		g.selectCompletion(g.completion.selected + 1)
+	case KeyArrowUp, KeyCtrlP:
+		g.selectCompletion(g.completion.selected - 1)
+	case KeyTab, KeyEnter:
+		return true, g.acceptCompletion()
+	case KeyEsc:
+		g.hideCompletion()
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
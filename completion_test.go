@@ -0,0 +1,82 @@
+package gocui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompletion(t *testing.T) {
+	g := &Gui{maxX: 40, maxY: 20}
+	v, _ := g.SetView("prompt", 0, 0, 30, 2, 0)
+	v.Editable = true
+	g.currentView = v
+
+	branches := []string{"feature/a", "feature/b", "main"}
+	calls := 0
+	v.CompletionProvider = func(content string, cursor int) []string {
+		calls++
+		token := content[strings.LastIndexAny(content[:cursor], " \n")+1 : cursor]
+		if token == "" {
+			return nil
+		}
+		var result []string
+		for _, branch := range branches {
+			if strings.HasPrefix(branch, token) {
+				result = append(result, branch)
+			}
+		}
+		return result
+	}
+
+	typeString := func(str string) {
+		for _, ch := range str {
+			ev := &GocuiEvent{Type: eventKey, Ch: ch}
+			if ch == ' ' {
+				ev = &GocuiEvent{Type: eventKey, Key: KeySpace}
+			}
+			assert.NoError(t, g.execKeybindings(v, ev))
+		}
+	}
+	press := func(key Key) {
+		assert.NoError(t, g.execKeybindings(v, &GocuiEvent{Type: eventKey, Key: key}))
+	}
+
+	typeString("git checkout f")
+	dropdown, err := g.View(CompletionViewName)
+	assert.NoError(t, err)
+	assert.Equal(t, " feature/a \n feature/b ", dropdown.Buffer())
+	x0, y0, _, _ := dropdown.Dimensions()
+	// the candidates start in the column of the cursor
+	assert.Equal(t, []int{13, 2}, []int{x0, y0})
+
+	press(KeyArrowDown)
+	press(KeyArrowDown)
+	press(KeyArrowUp)
+	assert.Equal(t, 1, g.completion.selected)
+	press(KeyTab)
+	assert.Equal(t, "git checkout feature/b", v.TextArea.GetContent())
+	_, err = g.View(CompletionViewName)
+	assert.True(t, errors.Is(err, ErrUnknownView))
+
+	// the token is replaced even if the cursor is in the middle of it
+	v.TextArea.Clear()
+	typeString("a ma")
+	// moving the cursor doesn't refresh the candidates
+	calls = 0
+	press(KeyArrowLeft)
+	assert.Zero(t, calls)
+	assert.NoError(t, g.updateCompletion(v))
+	assert.Equal(t, " main ", g.completion.dropdown.Buffer())
+	press(KeyEnter)
+	assert.Equal(t, "a main", v.TextArea.GetContent())
+	assert.Equal(t, 6, v.TextArea.GetCursor())
+
+	// Esc closes the dropdown without changing the content
+	typeString(" fe")
+	press(KeyEsc)
+	assert.Nil(t, g.completion)
+	assert.Equal(t, "a main fe", v.TextArea.GetContent())
+}
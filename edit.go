@@ -32,19 +32,21 @@ func SimpleEditor(v *View, key Key, ch rune, mod Modifier) bool {
 		v.TextArea.BackSpaceChar()
 	case key == KeyCtrlD || key == KeyDelete:
 		v.TextArea.DeleteChar()
-	case key == KeyArrowDown:
+	case key == KeyArrowDown && (mod&ModShift) == 0:
 		v.TextArea.MoveCursorDown()
-	case key == KeyArrowUp:
+	case key == KeyArrowUp && (mod&ModShift) == 0:
 		v.TextArea.MoveCursorUp()
-	case (key == KeyArrowLeft || ch == 'b') && (mod&ModAlt) != 0:
+	case (key == KeyArrowLeft || ch == 'b') && (mod&ModAlt) != 0,
+		key == KeyArrowLeft && (mod&ModCtrl) != 0:
 		v.TextArea.MoveLeftWord()
 	case key == KeyArrowLeft || key == KeyCtrlB:
 		v.TextArea.MoveCursorLeft()
-	case (key == KeyArrowRight || ch == 'f') && (mod&ModAlt) != 0:
+	case (key == KeyArrowRight || ch == 'f') && (mod&ModAlt) != 0,
+		key == KeyArrowRight && (mod&ModCtrl) != 0:
 		v.TextArea.MoveRightWord()
 	case key == KeyArrowRight || key == KeyCtrlF:
 		v.TextArea.MoveCursorRight()
-	case key == KeyEnter:
+	case key == KeyEnter && (mod&ModAlt) == 0:
 		v.TextArea.TypeCharacter("\n")
 	case key == KeySpace:
 		v.TextArea.TypeCharacter(" ")
@@ -0,0 +1,95 @@
+package gocui
+
+import (
+	"cmp"
+	"slices"
+)
+
+// SetFocusOrder sets the order in which FocusNext and FocusPrevious move the
+// focus between views. Views that don't exist or aren't visible are skipped.
+// Without an explicit order, the Focusable views take part in focus
+// traversal, ordered top to bottom and then left to right.
+func (g *Gui) SetFocusOrder(names ...string) {
+	g.focusOrder = names
+}
+
+// FocusNext moves the focus to the view after the current one in the focus
+// order, wrapping around at the end. It returns the newly focused view, or nil
+// if there are no views to focus.
+func (g *Gui) FocusNext() *View {
+	return g.moveFocus(1)
+}
+
+// FocusPrevious moves the focus to the view before the current one in the
+// focus order, wrapping around at the start. It returns the newly focused
+// view, or nil if there are no views to focus.
+func (g *Gui) FocusPrevious() *View {
+	return g.moveFocus(-1)
+}
+
+func (g *Gui) moveFocus(direction int) *View {
+	views := g.focusableViews()
+	if len(views) == 0 {
+		return nil
+	}
+
+	index := slices.Index(views, g.currentView)
+	if index == -1 && direction > 0 {
+		index = len(views) - 1
+	} else if index == -1 {
+		index = 0
+	}
+
+	next := views[(index+direction+len(views))%len(views)]
+	g.setCurrentView(next)
+	return next
+}
+
+// focusableViews returns the views that take part in focus traversal, in
+// focus order.
+func (g *Gui) focusableViews() []*View {
+	g.Mutexes.ViewsMutex.Lock()
+	defer g.Mutexes.ViewsMutex.Unlock()
+
+	var result []*View
+	if len(g.focusOrder) > 0 {
+		for _, name := range g.focusOrder {
+			for _, v := range g.views {
+				if v.name == name && v.Visible {
+					result = append(result, v)
+				}
+			}
+		}
+		return result
+	}
+
+	for _, v := range g.views {
+		if v.Focusable && v.Visible {
+			result = append(result, v)
+		}
+	}
+	slices.SortStableFunc(result, func(a, b *View) int {
+		return cmp.Or(cmp.Compare(a.y0, b.y0), cmp.Compare(a.x0, b.x0))
+	})
+	return result
+}
+
+// setCurrentView is the only place where currentView changes, so that the
+// OnBlur and OnFocus callbacks of the affected views are always called.
+func (g *Gui) setCurrentView(v *View) {
+	previous := g.currentView
+	if previous == v {
+		return
+	}
+
+	g.currentView = v
+	if g.completion != nil && g.completion.view != v {
+		g.hideCompletion()
+	}
+	if previous != nil && previous.OnBlur != nil {
+		previous.OnBlur()
+	}
+	if v != nil && v.OnFocus != nil {
+		v.OnFocus()
+	}
+}
@@ -0,0 +1,82 @@
+package gocui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFocusTraversal(t *testing.T) {
+	newFocusableView := func(name string, x0, y0 int) *View {
+		v := NewView(name, x0, y0, x0+5, y0+5, OutputNormal)
+		v.Focusable = true
+		return v
+	}
+
+	g := &Gui{}
+	bottom := newFocusableView("bottom", 0, 10)
+	right := newFocusableView("right", 10, 0)
+	left := newFocusableView("left", 0, 0)
+	other := NewView("other", 0, 20, 5, 25, OutputNormal)
+	g.views = []*View{bottom, right, left, other}
+
+	var events []string
+	for _, v := range g.views {
+		v.OnFocus = func() { events = append(events, "focus "+v.Name()) }
+		v.OnBlur = func() { events = append(events, "blur "+v.Name()) }
+	}
+
+	assert.Equal(t, left, g.FocusNext())
+	assert.Equal(t, right, g.FocusNext())
+	assert.Equal(t, bottom, g.FocusNext())
+	assert.Equal(t, left, g.FocusNext())
+	assert.Equal(t, bottom, g.FocusPrevious())
+
+	right.Visible = false
+	g.SetFocusOrder("bottom", "other", "right")
+	assert.Equal(t, other, g.FocusNext())
+	assert.Equal(t, bottom, g.FocusNext())
+
+	_, err := g.SetCurrentView("other")
+	assert.NoError(t, err)
+	_, err = g.SetCurrentView("other")
+	assert.NoError(t, err)
+	assert.NoError(t, g.DeleteView("other"))
+	assert.Nil(t, g.CurrentView())
+
+	assert.Equal(t, []string{
+		"focus left",
+		"blur left", "focus right",
+		"blur right", "focus bottom",
+		"blur bottom", "focus left",
+		"blur left", "focus bottom",
+		"blur bottom", "focus other",
+		"blur other", "focus bottom",
+		"blur bottom", "focus other",
+		"blur other",
+	}, events)
+}
+
+func TestFocusKeys(t *testing.T) {
+	g := &Gui{NextFocusKey: KeyTab, PrevFocusKey: KeyBacktab}
+	first := NewView("first", 0, 0, 5, 5, OutputNormal)
+	second := NewView("second", 0, 10, 5, 15, OutputNormal)
+	g.views = []*View{first, second}
+	g.SetFocusOrder("first", "second")
+	g.currentView = first
+
+	assert.NoError(t, g.execKeybindings(first, &GocuiEvent{Type: eventKey, Key: KeyTab}))
+	assert.Equal(t, second, g.CurrentView())
+	assert.NoError(t, g.execKeybindings(second, &GocuiEvent{Type: eventKey, Key: KeyBacktab}))
+	assert.Equal(t, first, g.CurrentView())
+
+	// an explicit keybinding takes precedence
+	handled := false
+	assert.NoError(t, g.SetKeybinding("", KeyTab, ModNone, func(*Gui, *View) error {
+		handled = true
+		return nil
+	}))
+	assert.NoError(t, g.execKeybindings(first, &GocuiEvent{Type: eventKey, Key: KeyTab}))
+	assert.True(t, handled)
+	assert.Equal(t, first, g.CurrentView())
+}
@@ -0,0 +1,211 @@
+package gocui
+
+import (
+	"github.com/rivo/uniseg"
+)
+
+// FrameEdge selects the top or the bottom edge of a view's frame.
+type FrameEdge int
+
+const (
+	FrameTop FrameEdge = iota
+	FrameBottom
+)
+
+// FrameSegment is a piece of text that is drawn on the frame of a view, in
+// addition to its title, subtitle and footer. Segments with the same edge and
+// alignment are drawn next to each other, in order. Left-aligned segments on
+// the top edge follow the title, and right-aligned ones are drawn before the
+// subtitle or, on the bottom edge, before the footer. Segments that don't fit
+// are cut off with an ellipsis.
+type FrameSegment struct {
+	// Text is the text of the segment. It may contain ANSI escape sequences,
+	// which take precedence over FgColor and BgColor.
+	Text string
+
+	// FgColor and BgColor are the colors of the segment. If they are
+	// ColorDefault, the segment is drawn in the colors of the title.
+	FgColor, BgColor Attribute
+
+	Edge      FrameEdge
+	Alignment Alignment
+
+	// OnClick, if set, is called when the segment is clicked.
+	OnClick func() error
+}
+
+// frameCell is a cell of a frame segment, with its position on the edge.
+type frameCell struct {
+	cell
+	x       int
+	segment *FrameSegment
+}
+
+// cells parses the segment's text into cells.
+func (s *FrameSegment) cells(mode OutputMode) []cell {
+	ei := newEscapeInterpreter(mode)
+	ei.curFgColor, ei.curBgColor = s.FgColor, s.BgColor
+
+	var cells []cell
+	state := -1
+	remaining := []byte(s.Text)
+	for len(remaining) > 0 {
+		var chr []byte
+		var width int
+		chr, remaining, width, state = uniseg.FirstGraphemeCluster(remaining, state)
+		isEscape, err := ei.parseOne(chr)
+		if err != nil {
+			ei.reset()
+			ei.curFgColor, ei.curBgColor = s.FgColor, s.BgColor
+			continue
+		}
+		if isEscape || width == 0 {
+			continue
+		}
+		cells = append(cells, cell{chr: string(chr), width: width, fgColor: ei.curFgColor, bgColor: ei.curBgColor})
+	}
+	return cells
+}
+
+// frameSegmentBounds returns the columns between which the frame segments of
+// the given edge of a view are drawn; end is exclusive.
+func (g *Gui) frameSegmentBounds(v *View, edge FrameEdge) (int, int) {
+	start, end := v.x0+2, v.x1-1
+	if edge == FrameTop {
+		if v.Title != "" || len(v.Tabs) > 0 {
+			x := v.x0 + 2 + uniseg.StringWidth(v.titlePrefix())
+			for _, c := range v.titleCells(v.x1 - 1 - x) {
+				x += c.width
+			}
+			start = x + 1
+		}
+		if subtitle := v.subtitle(); subtitle != "" {
+			if subtitleStart := v.x1 - 5 - uniseg.StringWidth(subtitle); subtitleStart >= v.x0 {
+				end = subtitleStart - 1
+			}
+		}
+	} else if v.Footer != "" && g.ShowListFooter && len(v.lines) > 0 {
+		if footerStart := v.x1 - 1 - uniseg.StringWidth(v.Footer); footerStart >= v.x0 {
+			end = footerStart - 1
+		}
+	}
+	return start, end
+}
+
+// frameSegmentCells lays out the frame segments of the given edge of a view.
+func (g *Gui) frameSegmentCells(v *View, edge FrameEdge) []frameCell {
+	if len(v.FrameSegments) == 0 {
+		return nil
+	}
+
+	// the cells of the left, centre and right groups, and the segments that
+	// they belong to
+	var groups [3][]cell
+	var groupSegments [3][]*FrameSegment
+	for _, segment := range v.FrameSegments {
+		if segment.Edge != edge {
+			continue
+		}
+		i := min(max(int(segment.Alignment), 0), 2)
+		cells := segment.cells(g.outputMode)
+		groups[i] = append(groups[i], cells...)
+		for range cells {
+			groupSegments[i] = append(groupSegments[i], segment)
+		}
+	}
+
+	width := func(cells []cell) int {
+		w := 0
+		for _, c := range cells {
+			w += c.width
+		}
+		return w
+	}
+	var result []frameCell
+	place := func(i int, x int) {
+		for j, c := range groups[i] {
+			result = append(result, frameCell{cell: c, x: x, segment: groupSegments[i][j]})
+			x += c.width
+		}
+	}
+	// the gap between groups
+	gap := func(cells []cell) int {
+		if len(cells) == 0 {
+			return 0
+		}
+		return 1
+	}
+
+	// truncates a group to the given width, dropping it if there is no room
+	fit := func(cells []cell, columns int) []cell {
+		if columns < 1 {
+			return nil
+		}
+		cells, _ = truncateLine(cells, nil, columns)
+		return cells
+	}
+
+	start, end := g.frameSegmentBounds(v, edge)
+
+	groups[AlignLeft] = fit(groups[AlignLeft], end-start)
+	left := width(groups[AlignLeft])
+	place(int(AlignLeft), start)
+	start += left + gap(groups[AlignLeft])
+
+	groups[AlignRight] = fit(groups[AlignRight], end-start)
+	right := width(groups[AlignRight])
+	place(int(AlignRight), end-right)
+	end -= right + gap(groups[AlignRight])
+
+	groups[AlignCenter] = fit(groups[AlignCenter], end-start)
+	center := width(groups[AlignCenter])
+	x := v.x0 + (v.Width()-center)/2
+	place(int(AlignCenter), max(min(x, end-center), start))
+
+	return result
+}
+
+// drawFrameSegments draws the frame segments of the given edge of a view.
+func (g *Gui) drawFrameSegments(v *View, edge FrameEdge, fgColor, bgColor Attribute) {
+	y := v.y0
+	if edge == FrameBottom {
+		y = v.y1
+	}
+	if y < 0 || y >= g.maxY {
+		return
+	}
+
+	for _, c := range g.frameSegmentCells(v, edge) {
+		if c.width == 0 || c.x < v.x0+1 || c.x+c.width > v.x1 {
+			continue
+		}
+		cellFgColor, cellBgColor := c.fgColor, c.bgColor
+		if cellFgColor == ColorDefault {
+			cellFgColor = fgColor
+		}
+		if cellBgColor == ColorDefault {
+			cellBgColor = bgColor
+		}
+		g.setCharacter(c.x, y, c.chr, cellFgColor, cellBgColor)
+	}
+}
+
+// frameSegmentAt returns the frame segment at the given screen position, or
+// nil if there is none.
+func (g *Gui) frameSegmentAt(v *View, x, y int) *FrameSegment {
+	edges := []FrameEdge{}
+	if y == v.y0 && v.hasFrameSide(TOP) {
+		edges = append(edges, FrameTop)
+	}
+	if y == v.y1 && v.hasFrameSide(BOTTOM) {
+		edges = append(edges, FrameBottom)
+	}
+	for _, edge := range edges {
+		for _, c := range g.frameSegmentCells(v, edge) {
+			if x >= c.x && x < c.x+c.width {
+				return c.segment
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package gocui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrawFrameSegments(t *testing.T) {
+	setupSimulationScreen(t, 30, 10)
+	g := &Gui{maxX: 30, maxY: 10}
+
+	v, _ := g.SetView("v", 0, 0, 29, 3, 0)
+	v.Title = "title"
+	v.Subtitle = "sub"
+	v.FrameSegments = []*FrameSegment{
+		{Text: "[a]", Edge: FrameTop},
+		{Text: "[b]", Edge: FrameTop},
+		{Text: "r", Edge: FrameTop, Alignment: AlignRight},
+		{Text: "\x1b[32mok\x1b[0m!", Edge: FrameBottom},
+		{Text: "mid", Edge: FrameBottom, Alignment: AlignCenter},
+		{Text: "end", Edge: FrameBottom, Alignment: AlignRight, FgColor: ColorRed},
+	}
+
+	assert.NoError(t, g.draw(v))
+	assert.Equal(t, "┌─title─[a][b]─────r─sub─────┐", screenRow(0, 29, 0))
+	assert.Equal(t, "└─ok!────────mid─────────end─┘", screenRow(0, 29, 3))
+
+	_, style, _ := Screen.Get(2, 3)
+	fg, _, _ := style.Decompose()
+	assert.Equal(t, getTcellColor(ColorGreen, OutputNormal), fg)
+	_, style, _ = Screen.Get(25, 3)
+	fg, _, _ = style.Decompose()
+	assert.Equal(t, getTcellColor(ColorRed, OutputNormal), fg)
+
+	// segments that don't fit are cut off
+	v.FrameSegments = []*FrameSegment{
+		{Text: "a very long segment", Edge: FrameTop},
+		{Text: "right", Edge: FrameBottom, Alignment: AlignRight},
+	}
+	g.ShowListFooter = true
+	v.Footer = "1 of 2"
+	v.SetContent("line")
+	assert.NoError(t, g.draw(v))
+	assert.Equal(t, "┌─title─a very long…─sub─────┐", screenRow(0, 29, 0))
+	assert.Equal(t, "└───────────────right─1 of 2─┘", screenRow(0, 29, 3))
+}
+
+func TestClickFrameSegment(t *testing.T) {
+	setupSimulationScreen(t, 30, 10)
+	g := &Gui{maxX: 30, maxY: 10}
+
+	v, _ := g.SetView("v", 0, 0, 21, 3, 0)
+	clicked := []string{}
+	v.FrameSegments = []*FrameSegment{
+		{Text: "[x]", Edge: FrameTop, Alignment: AlignRight, OnClick: func() error {
+			clicked = append(clicked, "close")
+			return nil
+		}},
+		{Text: "[ok]", Edge: FrameBottom, Alignment: AlignCenter, OnClick: func() error {
+			clicked = append(clicked, "ok")
+			return nil
+		}},
+	}
+
+	assert.NoError(t, g.onKey(&GocuiEvent{Type: eventMouse, Key: MouseLeft, MouseX: 19, MouseY: 0}))
+	assert.NoError(t, g.onKey(&GocuiEvent{Type: eventMouse, Key: MouseLeft, MouseX: 10, MouseY: 3}))
+	// not on a segment
+	assert.NoError(t, g.onKey(&GocuiEvent{Type: eventMouse, Key: MouseLeft, MouseX: 3, MouseY: 3}))
+	assert.Equal(t, []string{"close", "ok"}, clicked)
+}
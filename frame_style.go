@@ -0,0 +1,48 @@
+package gocui
+
+// FrameStyle is a named set of runes for drawing the frame of a view.
+type FrameStyle int
+
+const (
+	FrameStyleSingle FrameStyle = iota
+	FrameStyleRounded
+	FrameStyleDouble
+	FrameStyleHeavy
+	FrameStyleASCII
+	FrameStyleDashed
+)
+
+var frameStyleRunes = map[FrameStyle]string{
+	FrameStyleSingle:  "─│┌┐└┘├┤┬┴┼",
+	FrameStyleRounded: "─│╭╮╰╯├┤┬┴┼",
+	FrameStyleDouble:  "═║╔╗╚╝╠╣╦╩╬",
+	FrameStyleHeavy:   "━┃┏┓┗┛┣┫┳┻╋",
+	FrameStyleASCII:   "-|+++++++++",
+	FrameStyleDashed:  "╌╎┌┐└┘├┤┬┴┼",
+}
+
+// Runes returns the 11 frame runes of the style, including the junctions
+// that are used with Gui.SupportOverlaps, to be assigned to View.FrameRunes.
+func (s FrameStyle) Runes() []rune {
+	runes, ok := frameStyleRunes[s]
+	if !ok {
+		runes = frameStyleRunes[FrameStyleSingle]
+	}
+	return []rune(runes)
+}
+
+// hasFrameSide returns true if the frame of the view is drawn on the given
+// side, which is one of TOP, BOTTOM, LEFT and RIGHT.
+func (v *View) hasFrameSide(side byte) bool {
+	return v.Frame && (v.FrameSides == 0 || v.FrameSides&side != 0)
+}
+
+// frameInset returns the number of cells between the given side of the view
+// and its content. This is 1 unless the view has a frame that is not drawn on
+// that side; views without a frame leave room for one.
+func (v *View) frameInset(side byte) int {
+	if v.Frame && v.FrameSides != 0 && v.FrameSides&side == 0 {
+		return 0
+	}
+	return 1
+}
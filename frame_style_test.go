@@ -0,0 +1,108 @@
+package gocui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrameStyleRunes(t *testing.T) {
+	for _, style := range []FrameStyle{
+		FrameStyleSingle, FrameStyleRounded, FrameStyleDouble, FrameStyleHeavy, FrameStyleASCII, FrameStyleDashed,
+	} {
+		assert.Len(t, style.Runes(), 11)
+	}
+
+	v := NewView("v", 0, 0, 5, 5, OutputNormal)
+	v.FrameRunes = FrameStyleDouble.Runes()
+	v.Overlaps = TOP | LEFT
+	// the junctions of the style are used for overlapping edges
+	assert.Equal(t, '╬', corner(v, BOTTOM|RIGHT))
+	assert.Equal(t, '╩', corner(v, TOP|LEFT|RIGHT))
+}
+
+func TestDrawFrameStyle(t *testing.T) {
+	setupSimulationScreen(t, 20, 10)
+	g := &Gui{maxX: 20, maxY: 10}
+
+	v, _ := g.SetView("v", 0, 0, 5, 2, 0)
+	v.FrameRunes = FrameStyleRounded.Runes()
+	assert.NoError(t, g.draw(v))
+	assert.Equal(t, []string{"╭────╮", "│    │", "╰────╯"},
+		[]string{screenRow(0, 5, 0), screenRow(0, 5, 1), screenRow(0, 5, 2)})
+}
+
+func TestFrameSides(t *testing.T) {
+	setupSimulationScreen(t, 20, 10)
+	g := &Gui{maxX: 20, maxY: 10}
+
+	v, _ := g.SetView("v", 0, 0, 5, 2, 0)
+	v.Title = "t"
+	v.FrameSides = TOP
+	v.SetContent("abcdef\nghijkl")
+
+	w, h := v.InnerSize()
+	assert.Equal(t, 6, w)
+	assert.Equal(t, 2, h)
+
+	assert.NoError(t, g.draw(v))
+	assert.Equal(t, []string{"──t───", "abcdef", "ghijkl"},
+		[]string{screenRow(0, 5, 0), screenRow(0, 5, 1), screenRow(0, 5, 2)})
+
+	v.FrameSides = LEFT | BOTTOM
+	w, h = v.InnerSize()
+	assert.Equal(t, 5, w)
+	assert.Equal(t, 2, h)
+	assert.NoError(t, g.draw(v))
+	assert.Equal(t, []string{"│abcde", "│ghijk", "└─────"},
+		[]string{screenRow(0, 5, 0), screenRow(0, 5, 1), screenRow(0, 5, 2)})
+
+	// without a frame, views still leave room for one
+	v.Frame = false
+	w, h = v.InnerSize()
+	assert.Equal(t, 4, w)
+	assert.Equal(t, 1, h)
+}
+
+func TestMouseCoordinatesWithFrameSides(t *testing.T) {
+	g := &Gui{maxX: 40, maxY: 20}
+	v, _ := g.SetView("v", 0, 0, 20, 10, 0)
+	v.FrameSides = BOTTOM | RIGHT
+	v.SetContent("first\nsecond")
+
+	var clicked ViewMouseBindingOpts
+	assert.NoError(t, g.SetViewClickBinding(&ViewMouseBinding{
+		ViewName: "v",
+		Key:      MouseLeft,
+		Handler: func(opts ViewMouseBindingOpts) error {
+			clicked = opts
+			return nil
+		},
+	}))
+
+	assert.NoError(t, g.onKey(&GocuiEvent{Type: eventMouse, Key: MouseLeft, MouseX: 2, MouseY: 1}))
+	assert.Equal(t, 2, clicked.X)
+	assert.Equal(t, 1, clicked.Y)
+	assert.Equal(t, 2, v.CursorX())
+	assert.Equal(t, 1, v.CursorY())
+}
+
+func TestPartialFrameOverlaps(t *testing.T) {
+	setupSimulationScreen(t, 30, 10)
+	g := &Gui{maxX: 30, maxY: 10, SupportOverlaps: true}
+
+	left, _ := g.SetView("left", 0, 0, 10, 4, 0)
+	right, _ := g.SetView("right", 10, 0, 20, 4, LEFT)
+	right.FrameSides = TOP | BOTTOM
+	assert.NoError(t, g.draw(left))
+	assert.NoError(t, g.draw(right))
+
+	// the edges of the partial frame join the edge of the view on the left
+	assert.Equal(t, []string{"┌─────────┬──────────", "└─────────┴──────────"},
+		[]string{screenRow(0, 20, 0), screenRow(0, 20, 4)})
+
+	// without overlaps, the edges just extend to the corners
+	g.SupportOverlaps = false
+	assert.NoError(t, g.draw(right))
+	assert.Equal(t, "─", screenRow(10, 10, 0))
+}
@@ -7,6 +7,7 @@ require (
 	github.com/go-errors/errors v1.0.2
 	github.com/rivo/uniseg v0.4.7
 	github.com/stretchr/testify v1.7.0
+	golang.org/x/text v0.32.0
 )
 
 require (
@@ -16,6 +17,5 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	golang.org/x/sys v0.39.0 // indirect
 	golang.org/x/term v0.38.0 // indirect
-	golang.org/x/text v0.32.0 // indirect
 	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
 )
@@ -99,6 +99,14 @@ type ViewMouseBindingOpts struct {
 	X int // i.e. origin x + cursor x
 	Y int // i.e. origin y + cursor y
 
+	// the position of the mouse on the screen, e.g. for OpenContextMenu
+	ScreenX int
+	ScreenY int
+
+	// the name of the view that was clicked. If the event bubbled up from a
+	// child view, this is the child's name.
+	TargetViewName string
+
 	Key Key // which button was clicked (will be one of the Mouse* constants)
 
 	IsDoubleClick bool // true if this is a double click
@@ -146,6 +154,7 @@ type Gui struct {
 	userEvents        chan userEvent
 	views             []*View
 	currentView       *View
+	focusOrder        []string
 	managers          []Manager
 	keybindings       []*keybinding
 	focusHandler      func(bool) error
@@ -194,6 +203,29 @@ type Gui struct {
 	NextSearchMatchKey any
 	PrevSearchMatchKey any
 
+	// if set, these keys switch to the next/previous tab of the current view,
+	// if it has tabs and no keybinding handles them. They must either be of
+	// type Key or rune.
+	NextTabKey any
+	PrevTabKey any
+
+	// these keys move the focus to the next/previous view in the focus order
+	// (see SetFocusOrder), if no keybinding handles them. They must either be
+	// of type Key or rune.
+	NextFocusKey any
+	PrevFocusKey any
+
+	// these keys move the keyboard focus to the next/previous hyperlink in the
+	// current view, and open the focused hyperlink with the function set by
+	// SetOpenHyperlinkFunc, if no keybinding handles them. They default to
+	// ctrl+n, ctrl+p and enter, so that they don't shadow the focus keys. If
+	// they are set to the same keys as NextFocusKey/PrevFocusKey, moving past
+	// the last hyperlink moves the focus on to the next view. They must either
+	// be of type Key or rune.
+	NextHyperlinkKey any
+	PrevHyperlinkKey any
+	OpenHyperlinkKey any
+
 	ErrorHandler func(error) error
 
 	ShouldHandleMouseEvent func(view *View, key Key) bool
@@ -205,6 +237,22 @@ type Gui struct {
 	taskManager *TaskManager
 
 	lastHoverView *View
+
+	// ShadowColor is the background color of the shadows of views with
+	// Shadow set. Defaults to black.
+	ShadowColor Attribute
+
+	// TooltipDelay is how long the mouse pointer has to rest on a view before
+	// its tooltip is shown.
+	TooltipDelay   time.Duration
+	tooltipTimer   *time.Timer
+	pendingTooltip *pendingTooltip
+	tooltipVisible bool
+
+	contextMenus            []*contextMenu
+	contextMenuPreviousView *View
+
+	completion *completion
 }
 
 type NewGuiOpts struct {
@@ -270,6 +318,12 @@ func NewGui(opts NewGuiOpts) (*Gui, error) {
 	// default keys for when searching strings in a view
 	g.SearchEscapeKey = KeyEsc
 	g.NextSearchMatchKey = 'n'
+	g.NextFocusKey = KeyTab
+	g.PrevFocusKey = KeyBacktab
+	g.NextHyperlinkKey = KeyCtrlN
+	g.PrevHyperlinkKey = KeyCtrlP
+	g.OpenHyperlinkKey = KeyEnter
+	g.TooltipDelay = 500 * time.Millisecond
 	g.PrevSearchMatchKey = 'N'
 
 	g.playRecording = opts.PlayRecording
@@ -313,6 +367,14 @@ func (g *Gui) SetRune(x, y int, ch rune, fgColor, bgColor Attribute) error {
 	return nil
 }
 
+// setCharacter is like SetRune, but writes a whole grapheme cluster.
+func (g *Gui) setCharacter(x, y int, ch string, fgColor, bgColor Attribute) {
+	if x < 0 || y < 0 || x >= g.maxX || y >= g.maxY {
+		return
+	}
+	tcellSetCell(x, y, ch, fgColor, bgColor, g.outputMode)
+}
+
 // SetView creates a new view with its top-left corner at (x0, y0)
 // and the bottom-right one at (x1, y1). If a view with the same name
 // already exists, its dimensions are updated; otherwise, the error
@@ -509,29 +571,34 @@ func (g *Gui) ViewPosition(name string) (x0, y0, x1, y1 int, err error) {
 // DeleteView deletes a view by name.
 func (g *Gui) DeleteView(name string) error {
 	g.Mutexes.ViewsMutex.Lock()
-	defer g.Mutexes.ViewsMutex.Unlock()
 
 	for i, v := range g.views {
 		if v.name == name {
 			g.views = append(g.views[:i], g.views[i+1:]...)
+			g.Mutexes.ViewsMutex.Unlock()
+
+			if v == g.lastHoverView {
+				g.lastHoverView = nil
+			}
+			if v == g.currentView {
+				g.setCurrentView(nil)
+			}
 			return nil
 		}
 	}
+	g.Mutexes.ViewsMutex.Unlock()
 	return errors.Wrap(ErrUnknownView, 0)
 }
 
 // SetCurrentView gives the focus to a given view.
 func (g *Gui) SetCurrentView(name string) (*View, error) {
-	g.Mutexes.ViewsMutex.Lock()
-	defer g.Mutexes.ViewsMutex.Unlock()
-
-	for _, v := range g.views {
-		if v.name == name {
-			g.currentView = v
-			return v, nil
-		}
+	v, err := g.View(name)
+	if err != nil {
+		return nil, err
 	}
-	return nil, errors.Wrap(ErrUnknownView, 0)
+
+	g.setCurrentView(v)
+	return v, nil
 }
 
 // CurrentView returns the currently focused view, or nil if no view
@@ -549,20 +616,37 @@ func (g *Gui) CurrentView() *View {
 // on others it might report Ctrl as Alt. It's not consistent and therefore it's not recommended
 // to use with mouse keys.
 func (g *Gui) SetKeybinding(viewname string, key any, mod Modifier, handler func(*Gui, *View) error) error {
-	var kb *keybinding
+	_, err := g.setKeybinding(viewname, key, mod, handler)
+	return err
+}
 
-	k, ch, err := getKey(key)
+// SetDescribedKeybinding is like SetKeybinding, but also attaches a
+// description and a category to the keybinding. These are used by
+// ActiveKeybindings and ShowKeybindingHelp.
+func (g *Gui) SetDescribedKeybinding(viewname string, key any, mod Modifier, description string, category string, handler func(*Gui, *View) error) error {
+	kb, err := g.setKeybinding(viewname, key, mod, handler)
 	if err != nil {
 		return err
 	}
 
+	kb.description = description
+	kb.category = category
+	return nil
+}
+
+func (g *Gui) setKeybinding(viewname string, key any, mod Modifier, handler func(*Gui, *View) error) (*keybinding, error) {
+	k, ch, err := getKey(key)
+	if err != nil {
+		return nil, err
+	}
+
 	if g.isBlacklisted(k) {
-		return ErrBlacklisted
+		return nil, ErrBlacklisted
 	}
 
-	kb = newKeybinding(viewname, k, ch, mod, handler)
+	kb := newKeybinding(viewname, k, ch, mod, handler)
 	g.keybindings = append(g.keybindings, kb)
-	return nil
+	return kb, nil
 }
 
 // DeleteKeybinding deletes a keybinding.
@@ -615,6 +699,12 @@ func (g *Gui) SetViewClickBinding(binding *ViewMouseBinding) error {
 	return nil
 }
 
+func deleteViewMouseBindings(bindings []*ViewMouseBinding, viewName string) []*ViewMouseBinding {
+	return slices.DeleteFunc(bindings, func(binding *ViewMouseBinding) bool {
+		return binding.ViewName == viewName
+	})
+}
+
 // BlackListKeybinding adds a keybinding to the blacklist
 func (g *Gui) BlacklistKeybinding(k Key) error {
 	if slices.Contains(g.blacklist, k) {
@@ -742,7 +832,7 @@ func (f ManagerFunc) Layout(g *Gui) error {
 // keybindings.
 func (g *Gui) SetManager(managers ...Manager) {
 	g.managers = managers
-	g.currentView = nil
+	g.setCurrentView(nil)
 	g.views = nil
 	g.keybindings = nil
 	g.tabClickBindings = nil
@@ -880,16 +970,18 @@ func (g *Gui) drawFrameEdges(v *View, fgColor, bgColor Attribute) error {
 		runeH, runeV = v.FrameRunes[0], v.FrameRunes[1]
 	}
 
-	for x := v.x0 + 1; x < v.x1 && x < g.maxX; x++ {
+	// without a frame on the left or right, the horizontal edges extend to
+	// the view's corners, and likewise for the vertical ones
+	for x := v.x0 + v.frameInset(LEFT); x <= v.x1-v.frameInset(RIGHT) && x < g.maxX; x++ {
 		if x < 0 {
 			continue
 		}
-		if v.y0 > -1 && v.y0 < g.maxY {
+		if v.hasFrameSide(TOP) && v.y0 > -1 && v.y0 < g.maxY {
 			if err := g.SetRune(x, v.y0, runeH, fgColor, bgColor); err != nil {
 				return err
 			}
 		}
-		if v.y1 > -1 && v.y1 < g.maxY {
+		if v.hasFrameSide(BOTTOM) && v.y1 > -1 && v.y1 < g.maxY {
 			if err := g.SetRune(x, v.y1, runeH, fgColor, bgColor); err != nil {
 				return err
 			}
@@ -897,16 +989,16 @@ func (g *Gui) drawFrameEdges(v *View, fgColor, bgColor Attribute) error {
 	}
 
 	showScrollbar, realScrollbarStart, realScrollbarEnd := calcRealScrollbarStartEnd(v)
-	for y := v.y0 + 1; y < v.y1 && y < g.maxY; y++ {
+	for y := v.y0 + v.frameInset(TOP); y <= v.y1-v.frameInset(BOTTOM) && y < g.maxY; y++ {
 		if y < 0 {
 			continue
 		}
-		if v.x0 > -1 && v.x0 < g.maxX {
+		if v.hasFrameSide(LEFT) && v.x0 > -1 && v.x0 < g.maxX {
 			if err := g.SetRune(v.x0, y, runeV, fgColor, bgColor); err != nil {
 				return err
 			}
 		}
-		if v.x1 > -1 && v.x1 < g.maxX {
+		if v.hasFrameSide(RIGHT) && v.x1 > -1 && v.x1 < g.maxX {
 			runeToPrint := calcScrollbarRune(showScrollbar, realScrollbarStart, realScrollbarEnd, y, runeV)
 
 			if err := g.SetRune(v.x1, y, runeToPrint, fgColor, bgColor); err != nil {
@@ -941,7 +1033,7 @@ func calcRealScrollbarStartEnd(v *View) (bool, int, int) {
 
 	originY := v.OriginY()
 	scrollbarStart, scrollbarHeight := calcScrollbar(fullHeight, height, originY, height-1)
-	top := v.y0 + 1
+	top := v.contentY0()
 	realScrollbarStart := top + scrollbarStart
 	realScrollbarEnd := realScrollbarStart + scrollbarHeight
 
@@ -1006,11 +1098,35 @@ func cornerCustomRune(v *View, index byte) rune {
 }
 
 func corner(v *View, directions byte) rune {
-	index := v.Overlaps | directions
+	return junctionRune(v, v.Overlaps|directions)
+}
+
+// junctionRune returns the rune for a point of the frame from which lines go
+// in the given directions.
+func junctionRune(v *View, directions byte) rune {
 	if len(v.FrameRunes) >= 6 {
-		return cornerCustomRune(v, index)
+		return cornerCustomRune(v, directions)
 	}
-	return cornerRune(index)
+	return cornerRune(directions)
+}
+
+// partialFrameCorner returns the rune for the corner of a frame that is only
+// drawn on one of the corner's sides (given as e.g. TOP|LEFT). The side ends
+// in a junction with the edges of the views that it overlaps with.
+func partialFrameCorner(v *View, sides byte) rune {
+	opposite := map[byte]byte{TOP: BOTTOM, BOTTOM: TOP, LEFT: RIGHT, RIGHT: LEFT}
+	horizontal, vertical := sides&(TOP|BOTTOM), sides&(LEFT|RIGHT)
+
+	directions := v.Overlaps & sides
+	// an overlapping view draws its edge where the side of this view is
+	// missing
+	if v.hasFrameSide(horizontal) || v.Overlaps&horizontal != 0 {
+		directions |= opposite[vertical]
+	}
+	if v.hasFrameSide(vertical) || v.Overlaps&vertical != 0 {
+		directions |= opposite[horizontal]
+	}
+	return junctionRune(v, directions)
 }
 
 // drawFrameCorners draws the corners of the view.
@@ -1039,13 +1155,29 @@ func (g *Gui) drawFrameCorners(v *View, fgColor, bgColor Attribute) error {
 	}
 
 	corners := []struct {
-		x, y int
-		ch   rune
-	}{{v.x0, v.y0, runeTL}, {v.x1, v.y0, runeTR}, {v.x0, v.y1, runeBL}, {v.x1, v.y1, runeBR}}
+		x, y  int
+		ch    rune
+		sides byte
+	}{
+		{v.x0, v.y0, runeTL, TOP | LEFT},
+		{v.x1, v.y0, runeTR, TOP | RIGHT},
+		{v.x0, v.y1, runeBL, BOTTOM | LEFT},
+		{v.x1, v.y1, runeBR, BOTTOM | RIGHT},
+	}
 
 	for _, c := range corners {
+		ch := c.ch
+		// a corner is only drawn where two sides of the frame meet, except
+		// that with overlaps, a side ends in a junction with the views next
+		// to it
+		if v.FrameSides != 0 && v.FrameSides&c.sides != c.sides {
+			if !g.SupportOverlaps || v.FrameSides&c.sides == 0 {
+				continue
+			}
+			ch = partialFrameCorner(v, c.sides)
+		}
 		if c.x >= 0 && c.y >= 0 && c.x < g.maxX && c.y < g.maxY {
-			if err := g.SetRune(c.x, c.y, c.ch, fgColor, bgColor); err != nil {
+			if err := g.SetRune(c.x, c.y, ch, fgColor, bgColor); err != nil {
 				return err
 			}
 		}
@@ -1059,46 +1191,16 @@ func (g *Gui) drawTitle(v *View, fgColor, bgColor Attribute) error {
 		return nil
 	}
 
-	tabs := v.Tabs
-	prefix := v.TitlePrefix
-	if prefix != "" {
-		if len(v.FrameRunes) > 0 {
-			prefix += string(v.FrameRunes[0])
-		} else {
-			prefix += "─"
-		}
-	}
-	separator := " - "
-	charIndex := 0
-	currentTabStart := -1
-	currentTabEnd := -1
-	if len(tabs) == 0 {
-		tabs = []string{v.Title}
-	} else {
-		for i, tab := range tabs {
-			if i == v.TabIndex {
-				currentTabStart = charIndex
-				currentTabEnd = charIndex + len(tab)
-				break
-			}
-			charIndex += len(tab)
-			if i < len(tabs)-1 {
-				charIndex += len(separator)
-			}
-		}
-	}
-
-	str := strings.Join(tabs, separator)
-
 	x := v.x0 + 2
-	for _, ch := range prefix {
+	for _, ch := range v.titlePrefix() {
 		if err := g.SetRune(x, v.y0, ch, fgColor, bgColor); err != nil {
 			return err
 		}
 		x += uniseg.StringWidth(string(ch))
 	}
-	for i, ch := range str {
+	for _, c := range v.titleCells(v.x1 - 1 - x) {
 		if x < 0 {
+			x += c.width
 			continue
 		} else if x > v.x1-2 || x >= g.maxX {
 			break
@@ -1111,32 +1213,30 @@ func (g *Gui) drawTitle(v *View, fgColor, bgColor Attribute) error {
 			currentBgColor = v.BgColor
 		}
 
-		if i >= currentTabStart && i <= currentTabEnd {
+		if c.selected {
 			currentFgColor = v.SelFgColor
 			if v != g.currentView {
 				currentFgColor &= ^AttrBold
 			}
 		}
-		if err := g.SetRune(x, v.y0, ch, currentFgColor, currentBgColor); err != nil {
-			return err
-		}
-		x += uniseg.StringWidth(string(ch))
+		g.setCharacter(x, v.y0, c.chr, currentFgColor, currentBgColor)
+		x += c.width
 	}
 	return nil
 }
 
 // drawSubtitle draws the subtitle of the view.
-func (g *Gui) drawSubtitle(v *View, fgColor, bgColor Attribute) error {
+func (g *Gui) drawSubtitle(v *View, subtitle string, fgColor, bgColor Attribute) error {
 	if v.y0 < 0 || v.y0 >= g.maxY {
 		return nil
 	}
 
-	start := v.x1 - 5 - uniseg.StringWidth(v.Subtitle)
+	start := v.x1 - 5 - uniseg.StringWidth(subtitle)
 	if start < v.x0 {
 		return nil
 	}
 	x := start
-	for _, ch := range v.Subtitle {
+	for _, ch := range subtitle {
 		if x >= v.x1 {
 			break
 		}
@@ -1222,6 +1322,7 @@ func (g *Gui) ForceRedrawViews(views ...*View) error {
 	for _, v := range views {
 		v.draw()
 	}
+	g.drawEffectsAbove(views)
 
 	Screen.Show()
 	return nil
@@ -1241,7 +1342,8 @@ func (g *Gui) draw(v *View) error {
 		if curview := g.currentView; curview != nil {
 			vMaxX, vMaxY := curview.InnerSize()
 			if curview.cx >= 0 && curview.cx < vMaxX && curview.cy >= 0 && curview.cy < vMaxY {
-				cx, cy := curview.x0+curview.cx+1, curview.y0+curview.cy+1
+				cx := curview.contentX0() + curview.logicalToVisualX(curview.cx+curview.ox, curview.cy+curview.oy) - curview.ox
+				cy := curview.contentY0() + curview.cy
 				Screen.ShowCursor(cx, cy)
 			} else {
 				Screen.HideCursor()
@@ -1251,6 +1353,7 @@ func (g *Gui) draw(v *View) error {
 		Screen.HideCursor()
 	}
 
+	g.drawBackdrop(v)
 	v.draw()
 
 	if v.Frame {
@@ -1273,29 +1376,46 @@ func (g *Gui) draw(v *View) error {
 			}
 		}
 
+		subtitle := v.subtitle()
+		if err := v.TextArea.ValidationError(); err != nil && v.Editable {
+			if v.ValidationErrorFrameColor != ColorDefault {
+				frameColor = v.ValidationErrorFrameColor
+			}
+		}
+
 		if err := g.drawFrameEdges(v, frameColor, bgColor); err != nil {
 			return err
 		}
 		if err := g.drawFrameCorners(v, frameColor, bgColor); err != nil {
 			return err
 		}
-		if v.Title != "" || len(v.Tabs) > 0 {
-			if err := g.drawTitle(v, fgColor, bgColor); err != nil {
-				return err
+		if v.hasFrameSide(TOP) {
+			if v.Title != "" || len(v.Tabs) > 0 {
+				if err := g.drawTitle(v, fgColor, bgColor); err != nil {
+					return err
+				}
 			}
-		}
-		if v.Subtitle != "" {
-			if err := g.drawSubtitle(v, fgColor, bgColor); err != nil {
-				return err
+			if subtitle != "" {
+				if err := g.drawSubtitle(v, subtitle, fgColor, bgColor); err != nil {
+					return err
+				}
 			}
+			g.drawFrameSegments(v, FrameTop, fgColor, bgColor)
 		}
-		if v.Footer != "" && g.ShowListFooter {
-			if err := g.drawListFooter(v, fgColor, bgColor); err != nil {
-				return err
+		if v.hasFrameSide(BOTTOM) {
+			if v.Footer != "" && g.ShowListFooter {
+				if err := g.drawListFooter(v, fgColor, bgColor); err != nil {
+					return err
+				}
 			}
+			g.drawFrameSegments(v, FrameBottom, fgColor, bgColor)
 		}
 	}
 
+	if v.Shadow {
+		g.drawShadow(v)
+	}
+
 	return nil
 }
 
@@ -1320,24 +1440,41 @@ func (g *Gui) onKey(ev *GocuiEvent) error {
 			ev.Key = KeyEnter
 		}
 
+		g.hideTooltip()
 		err := g.execKeybindings(g.currentView, ev)
 		if err != nil {
 			return err
 		}
 
 	case eventMouse:
+		g.hideTooltip()
 		mx, my := ev.MouseX, ev.MouseY
 		v, err := g.VisibleViewByPosition(mx, my)
+		if g.completion != nil && (err != nil || v != g.completion.dropdown) {
+			g.hideCompletion()
+		}
+		if len(g.contextMenus) > 0 && (err != nil || !g.isContextMenuView(v)) {
+			// a click outside of the context menu only dismisses it
+			if ev.Key != MouseRelease && !IsMouseScrollKey(ev.Key) {
+				return g.CloseContextMenu()
+			}
+			break
+		}
 		if err != nil {
 			break
 		}
 
 		// newCx and newCy are relative to the view port, i.e. to the visible area of the view
-		newCx := mx - v.x0 - 1
-		newCy := my - v.y0 - 1
+		newCx := mx - v.contentX0()
+		newCy := my - v.contentY0()
+		if newCx < 0 && mx > v.x0 {
+			// a click in the gutter goes to the start of the line
+			newCx = 0
+		}
 		// newX and newY are relative to the view's content, independent of its scroll position
-		newX := newCx + v.ox
 		newY := newCy + v.oy
+		newX := v.visualToLogicalX(newCx+v.ox, newY)
+		newCx = newX - v.ox
 		// if view is editable don't go further than the furthest character for that line
 		if v.Editable {
 			if newY < 0 {
@@ -1358,10 +1495,12 @@ func (g *Gui) onKey(ev *GocuiEvent) error {
 			}
 		}
 
-		if ev.Key == MouseLeft && (ev.Mod&ModMotion) == 0 && !v.Editable && g.openHyperlink != nil {
+		if ev.Key == MouseLeft && (ev.Mod&ModMotion) == 0 && !v.Editable {
 			if newY >= 0 && newY <= len(v.viewLines)-1 && newX >= 0 && newX <= len(v.viewLines[newY].line)-1 {
 				if link := v.viewLines[newY].line[newX].hyperlink; link != "" {
-					return g.openHyperlink(link, v.name)
+					if handled, err := g.openLink(v, link); handled {
+						return err
+					}
 				}
 			}
 		}
@@ -1387,7 +1526,30 @@ func (g *Gui) onKey(ev *GocuiEvent) error {
 			}
 		}
 
-		if v.Frame && my == v.y0 {
+		if v.MultiSelect && ev.Key == MouseLeft && (ev.Mod&ModMotion) == 0 {
+			if ev.Mod&ModCtrl != 0 {
+				v.ToggleLineSelected(v.viewLineToLineIdx(newY))
+			} else if ev.Mod&ModShift != 0 {
+				v.ExtendLineSelection(v.viewLineToLineIdx(newY))
+			}
+		}
+
+		// shift and ctrl are reported for clicks for the sake of selecting
+		// lines, but clicks with them still go to the bindings of plain clicks
+		// if there are no bindings for them
+		if ev.Mod&(ModShift|ModCtrl) != 0 && !g.hasMouseBinding(v, ev.Key, ev.Mod) {
+			plain := *ev
+			plain.Mod &^= ModShift | ModCtrl
+			ev = &plain
+		}
+
+		if v.Frame && (my == v.y0 || my == v.y1) && ev.Key == MouseLeft && (ev.Mod&ModMotion) == 0 {
+			if segment := g.frameSegmentAt(v, mx, my); segment != nil && segment.OnClick != nil {
+				return segment.OnClick()
+			}
+		}
+
+		if v.hasFrameSide(TOP) && my == v.y0 {
 			if len(v.Tabs) > 0 {
 				tabIndex := v.GetClickedTabIndex(mx - v.x0)
 
@@ -1403,7 +1565,7 @@ func (g *Gui) onKey(ev *GocuiEvent) error {
 
 		if IsMouseKey(ev.Key) {
 			isDoubleClick := g.recordClickInfo(newX, newY, ev.Key, v)
-			opts := ViewMouseBindingOpts{X: newX, Y: newY, Key: ev.Key, IsDoubleClick: isDoubleClick}
+			opts := ViewMouseBindingOpts{X: newX, Y: newY, ScreenX: mx, ScreenY: my, Key: ev.Key, IsDoubleClick: isDoubleClick}
 			matched, err := g.execMouseKeybindings(v, ev, opts)
 			if err != nil {
 				return err
@@ -1418,17 +1580,13 @@ func (g *Gui) onKey(ev *GocuiEvent) error {
 		}
 
 	case eventMouseMove:
+		g.hideTooltip()
 		mx, my := ev.MouseX, ev.MouseY
 		v, err := g.VisibleViewByPosition(mx, my)
 		if err != nil {
-			break
-		}
-		if g.lastHoverView != nil && g.lastHoverView != v {
-			g.lastHoverView.lastHoverPosition = nil
-			g.lastHoverView.hoveredHyperlink = nil
+			v = nil
 		}
-		g.lastHoverView = v
-		v.onMouseMove(mx, my)
+		g.onMouseMove(v, mx, my)
 
 	default:
 	}
@@ -1462,7 +1620,35 @@ func (g *Gui) recordClickInfo(x, y int, key Key, v *View) bool {
 	return isDoubleClick
 }
 
+// execMouseKeybindings executes the view mouse bindings that match the event,
+// starting with the clicked view and bubbling up through its ParentView chain
+// until a handler handles the event. A handler lets the event bubble up by
+// returning ErrKeybindingNotHandled. The coordinates in opts are relative to
+// the content of each view in the chain.
 func (g *Gui) execMouseKeybindings(view *View, ev *GocuiEvent, opts ViewMouseBindingOpts) (bool, error) {
+	opts.TargetViewName = view.Name()
+
+	visited := map[*View]bool{}
+	for v := view; v != nil && !visited[v]; v = v.ParentView {
+		visited[v] = true
+		if v != view {
+			opts.Y = ev.MouseY - v.contentY0() + v.oy
+			opts.X = v.visualToLogicalX(ev.MouseX-v.contentX0()+v.ox, opts.Y)
+		}
+
+		err := g.execViewMouseKeybindings(v, ev, opts)
+		if !errors.Is(err, ErrKeybindingNotHandled) {
+			return true, err
+		}
+	}
+
+	return false, nil
+}
+
+// execViewMouseKeybindings executes the first mouse binding of the given view
+// that matches the event and handles it. It returns ErrKeybindingNotHandled if
+// there is no such binding.
+func (g *Gui) execViewMouseKeybindings(view *View, ev *GocuiEvent, opts ViewMouseBindingOpts) error {
 	isMatch := func(binding *ViewMouseBinding) bool {
 		return binding.ViewName == view.Name() &&
 			ev.Key == binding.Key &&
@@ -1471,20 +1657,45 @@ func (g *Gui) execMouseKeybindings(view *View, ev *GocuiEvent, opts ViewMouseBin
 
 	// first pass looks for ones that match the focused view
 	for _, binding := range g.viewMouseBindings {
-		if isMatch(binding) && binding.FocusedView != "" && binding.FocusedView == g.currentView.Name() {
+		if isMatch(binding) && binding.FocusedView != "" && g.currentView != nil && binding.FocusedView == g.currentView.Name() {
 			if err := binding.Handler(opts); !errors.Is(err, ErrKeybindingNotHandled) {
-				return true, err
+				return err
 			}
 		}
 	}
 
 	for _, binding := range g.viewMouseBindings {
 		if isMatch(binding) && binding.FocusedView == "" {
-			return true, binding.Handler(opts)
+			if err := binding.Handler(opts); !errors.Is(err, ErrKeybindingNotHandled) {
+				return err
+			}
 		}
 	}
 
-	return false, nil
+	return ErrKeybindingNotHandled
+}
+
+// hasMouseBinding returns true if there is a binding for the given mouse key
+// and modifier in the given view, one of its parents, or globally.
+func (g *Gui) hasMouseBinding(view *View, key Key, mod Modifier) bool {
+	viewNames := map[string]bool{"": true}
+	visited := map[*View]bool{}
+	for v := view; v != nil && !visited[v]; v = v.ParentView {
+		visited[v] = true
+		viewNames[v.Name()] = true
+	}
+
+	for _, binding := range g.viewMouseBindings {
+		if viewNames[binding.ViewName] && binding.Key == key && binding.Modifier == mod {
+			return true
+		}
+	}
+	for _, kb := range g.keybindings {
+		if viewNames[kb.viewName] && kb.key == key && kb.ch == 0 && kb.mod == mod {
+			return true
+		}
+	}
+	return false
 }
 
 func IsMouseKey(key any) bool {
@@ -1544,13 +1755,17 @@ func (g *Gui) execKeybindings(v *View, ev *GocuiEvent) error {
 		}
 	}
 
+	if handled, err := g.execCompletionKeybindings(v, ev); handled {
+		return err
+	}
+
 	var err error
 
 	for _, kb := range g.keybindings {
 		if kb.handler == nil {
 			continue
 		}
-		if !kb.matchKeypress(ev.Key, ev.Ch, ev.Mod) {
+		if !kb.matchKeypress(ev) {
 			continue
 		}
 		if g.matchView(v, kb) {
@@ -1576,15 +1791,47 @@ func (g *Gui) execKeybindings(v *View, ev *GocuiEvent) error {
 		}
 	}
 
+	if handled, err := g.execHistoryKeybindings(v, ev); handled {
+		return err
+	}
+
 	if g.currentView != nil && g.currentView.Editable && g.currentView.Editor != nil {
+		content := g.currentView.TextArea.GetUnwrappedContent()
 		matched := g.currentView.Editor.Edit(g.currentView, ev.Key, ev.Ch, ev.Mod)
 		if matched {
+			// only refresh the completion when the content changed, so that
+			// e.g. moving the cursor doesn't ask the provider for candidates
+			if g.currentView.TextArea.GetUnwrappedContent() != content {
+				return g.updateCompletion(g.currentView)
+			}
 			return nil
 		}
 	}
 
 	if globalKb != nil {
-		err = g.execKeybinding(v, globalKb)
+		return g.execKeybinding(v, globalKb)
+	}
+
+	if v != nil && len(v.Tabs) > 1 && !v.Editable {
+		if g.NextTabKey != nil && eventMatchesKey(ev, g.NextTabKey) {
+			return g.selectTab(v, v.TabIndex+1)
+		} else if g.PrevTabKey != nil && eventMatchesKey(ev, g.PrevTabKey) {
+			return g.selectTab(v, v.TabIndex-1)
+		}
+	}
+
+	if handled, err := g.execHyperlinkKeybindings(v, ev); handled {
+		return err
+	}
+
+	if g.NextFocusKey != nil && eventMatchesKey(ev, g.NextFocusKey) {
+		if g.FocusNext() != nil {
+			return nil
+		}
+	} else if g.PrevFocusKey != nil && eventMatchesKey(ev, g.PrevFocusKey) {
+		if g.FocusPrevious() != nil {
+			return nil
+		}
 	}
 	return err
 }
@@ -0,0 +1,73 @@
+package gocui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecMouseKeybindingsBubbling(t *testing.T) {
+	g := &Gui{}
+	outer := NewView("outer", 0, 0, 40, 20, OutputNormal)
+	middle := NewView("middle", 5, 2, 30, 15, OutputNormal)
+	middle.ParentView = outer
+	inner := NewView("inner", 10, 4, 20, 10, OutputNormal)
+	inner.ParentView = middle
+	outer.oy = 3
+	g.views = []*View{outer, middle, inner}
+
+	var calls []string
+	binding := func(viewName string, err error) *ViewMouseBinding {
+		return &ViewMouseBinding{
+			ViewName: viewName,
+			Key:      MouseLeft,
+			Handler: func(opts ViewMouseBindingOpts) error {
+				calls = append(calls, viewName)
+				assert.Equal(t, "inner", opts.TargetViewName)
+				switch viewName {
+				case "middle":
+					assert.Equal(t, 6, opts.X)
+					assert.Equal(t, 4, opts.Y)
+				case "outer":
+					assert.Equal(t, 11, opts.X)
+					assert.Equal(t, 9, opts.Y)
+				}
+				return err
+			},
+		}
+	}
+
+	ev := &GocuiEvent{Type: eventMouse, Key: MouseLeft, MouseX: 12, MouseY: 7}
+	opts := ViewMouseBindingOpts{X: 1, Y: 2, Key: MouseLeft}
+
+	// no bindings at all
+	matched, err := g.execMouseKeybindings(inner, ev, opts)
+	assert.NoError(t, err)
+	assert.False(t, matched)
+
+	// the event skips views without a binding and bubbles up to any depth
+	assert.NoError(t, g.SetViewClickBinding(binding("outer", nil)))
+	matched, err = g.execMouseKeybindings(inner, ev, opts)
+	assert.NoError(t, err)
+	assert.True(t, matched)
+	assert.Equal(t, []string{"outer"}, calls)
+
+	// a handler that handles the event stops the propagation
+	calls = nil
+	assert.NoError(t, g.SetViewClickBinding(binding("middle", nil)))
+	matched, err = g.execMouseKeybindings(inner, ev, opts)
+	assert.NoError(t, err)
+	assert.True(t, matched)
+	assert.Equal(t, []string{"middle"}, calls)
+
+	// returning ErrKeybindingNotHandled lets the event bubble up
+	calls = nil
+	g.viewMouseBindings = nil
+	assert.NoError(t, g.SetViewClickBinding(binding("inner", ErrKeybindingNotHandled)))
+	assert.NoError(t, g.SetViewClickBinding(binding("middle", ErrKeybindingNotHandled)))
+	assert.NoError(t, g.SetViewClickBinding(binding("outer", nil)))
+	matched, err = g.execMouseKeybindings(inner, ev, opts)
+	assert.NoError(t, err)
+	assert.True(t, matched)
+	assert.Equal(t, []string{"inner", "middle", "outer"}, calls)
+}
@@ -0,0 +1,134 @@
+package gocui
+
+import (
+	"strconv"
+
+	"github.com/rivo/uniseg"
+)
+
+// LineNumberMode selects how a view's gutter shows line numbers.
+type LineNumberMode int
+
+const (
+	LineNumbersOff LineNumberMode = iota
+	LineNumbersAbsolute
+	// LineNumbersRelative shows the distance of each line to the cursor's
+	// line, and the absolute number on the cursor's line itself.
+	LineNumbersRelative
+)
+
+// GutterWidth returns the width of the view's gutter, i.e. of the sign column
+// plus the line numbers. The gutter is drawn between the left edge of the
+// view and its content, so it is not part of InnerWidth.
+func (v *View) GutterWidth() int {
+	return min(v.signColumnWidth()+v.lineNumbersWidth(), max(v.Width()-v.contentInset(LEFT)-v.contentInset(RIGHT), 0))
+}
+
+func (v *View) signColumnWidth() int {
+	if v.Signs == nil {
+		return 0
+	}
+	return max(v.SignColumnWidth, 1)
+}
+
+// lineNumbersWidth returns the width of the line numbers, including the space
+// that separates them from the content.
+func (v *View) lineNumbersWidth() int {
+	if v.LineNumbers == LineNumbersOff {
+		return 0
+	}
+
+	digits := 1
+	for n := len(v.lines); n >= 10; n /= 10 {
+		digits++
+	}
+	return digits + 1
+}
+
+// contentX0 returns the screen column of the first column of the view's
+// content.
+func (v *View) contentX0() int {
+	return v.x0 + v.contentInset(LEFT) + v.GutterWidth()
+}
+
+// drawGutter draws the sign column and line numbers for the visible lines,
+// given the index of the first visible view line and the number of rows.
+func (v *View) drawGutter(start int, maxY int) {
+	width := v.GutterWidth()
+	if width == 0 {
+		return
+	}
+
+	fgColor, bgColor := v.GutterFgColor, v.GutterBgColor
+	if fgColor == ColorDefault {
+		fgColor = v.FgColor
+	}
+	if bgColor == ColorDefault {
+		bgColor = v.BgColor
+	}
+
+	cursorLine := -1
+	if cy := v.oy + v.cy; cy >= 0 && cy < len(v.viewLines) {
+		cursorLine = v.viewLines[cy].linesY
+	}
+	signWidth := v.signColumnWidth()
+	numberWidth := width - signWidth
+
+	for y := range maxY {
+		x := v.x0 + v.contentInset(LEFT)
+		screenY := v.contentY0() + y
+		setCells := func(str string, cellWidth int, fgColor Attribute) {
+			state := -1
+			for str != "" && cellWidth > 0 {
+				var ch string
+				var w int
+				ch, str, w, state = uniseg.FirstGraphemeClusterInString(str, state)
+				if w > cellWidth {
+					break
+				}
+				tcellSetCell(x, screenY, ch, fgColor, bgColor, v.outMode)
+				x += w
+				cellWidth -= w
+			}
+			for range cellWidth {
+				tcellSetCell(x, screenY, " ", fgColor, bgColor, v.outMode)
+				x++
+			}
+		}
+
+		// continuation lines of wrapped lines get an empty gutter
+		lineIdx := -1
+		if vy := start + y; vy >= 0 && vy < len(v.viewLines) && v.viewLines[vy].linesX == 0 {
+			lineIdx = v.viewLines[vy].linesY
+		}
+
+		sign, signFgColor := "", fgColor
+		if lineIdx >= 0 && signWidth > 0 {
+			var color Attribute
+			sign, color = v.Signs(lineIdx)
+			if color != ColorDefault {
+				signFgColor = color
+			}
+		}
+		setCells(sign, signWidth, signFgColor)
+
+		if numberWidth <= 0 {
+			continue
+		}
+		number, numberFgColor := "", fgColor
+		if lineIdx >= 0 {
+			n := lineIdx + 1
+			if v.LineNumbers == LineNumbersRelative && lineIdx != cursorLine && cursorLine >= 0 {
+				n = max(lineIdx-cursorLine, cursorLine-lineIdx)
+			}
+			if lineIdx == cursorLine {
+				numberFgColor |= AttrBold
+			}
+			number = strconv.Itoa(n)
+		}
+		// numbers are right-aligned, followed by a space
+		padding := max(numberWidth-1-len(number), 0)
+		setCells("", padding, fgColor)
+		setCells(number+" ", numberWidth-padding, numberFgColor)
+	}
+}
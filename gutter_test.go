@@ -0,0 +1,116 @@
+package gocui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupSimulationScreen(t *testing.T, width, height int) {
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatal(err)
+	}
+	s.SetSize(width, height)
+	previous := Screen
+	Screen = s
+	t.Cleanup(func() {
+		s.Fini()
+		Screen = previous
+	})
+}
+
+func screenRow(x0, x1, y int) string {
+	var b strings.Builder
+	for x := x0; x <= x1; x++ {
+		str, _, _ := Screen.Get(x, y)
+		b.WriteString(str)
+	}
+	return b.String()
+}
+
+func TestGutterWidth(t *testing.T) {
+	v := NewView("v", 0, 0, 21, 10, OutputNormal)
+	v.SetContent(strings.Repeat("line\n", 11))
+
+	assert.Equal(t, 0, v.GutterWidth())
+	assert.Equal(t, 20, v.InnerWidth())
+
+	v.LineNumbers = LineNumbersAbsolute
+	assert.Equal(t, 3, v.GutterWidth())
+	assert.Equal(t, 17, v.InnerWidth())
+
+	v.Signs = func(int) (string, Attribute) { return "", ColorDefault }
+	assert.Equal(t, 4, v.GutterWidth())
+	v.SignColumnWidth = 2
+	assert.Equal(t, 5, v.GutterWidth())
+	assert.Equal(t, 15, v.InnerWidth())
+}
+
+func TestDrawGutter(t *testing.T) {
+	setupSimulationScreen(t, 20, 10)
+
+	v := NewView("v", 0, 0, 11, 5, OutputNormal)
+	v.Wrap = true
+	v.LineNumbers = LineNumbersAbsolute
+	v.Signs = func(lineIdx int) (string, Attribute) {
+		if lineIdx == 1 {
+			return "+", ColorGreen
+		}
+		return "", ColorDefault
+	}
+	v.SetContent("one\nwrapped line\nthree")
+
+	v.draw()
+	assert.Equal(t, []string{
+		" 1 one    ",
+		"+2 wrapped",
+		"   line   ",
+		" 3 three  ",
+	}, []string{screenRow(1, 10, 1), screenRow(1, 10, 2), screenRow(1, 10, 3), screenRow(1, 10, 4)})
+
+	_, style, _ := Screen.Get(1, 2)
+	fg, _, _ := style.Decompose()
+	assert.Equal(t, getTcellColor(ColorGreen, OutputNormal), fg)
+
+	// the content keeps its coordinates
+	word, _ := v.Word(0, 1)
+	assert.Equal(t, "wrapped", word)
+
+	v.LineNumbers = LineNumbersRelative
+	v.SetCursor(0, 3)
+	v.draw()
+	assert.Equal(t, " 2", screenRow(1, 2, 1))
+	assert.Equal(t, "+1", screenRow(1, 2, 2))
+	assert.Equal(t, " 3", screenRow(1, 2, 4))
+}
+
+func TestMouseCoordinatesWithGutter(t *testing.T) {
+	g := &Gui{maxX: 40, maxY: 20}
+	v, _ := g.SetView("v", 0, 0, 20, 10, 0)
+	v.LineNumbers = LineNumbersAbsolute
+	v.SetContent("first\nsecond")
+
+	var clicked ViewMouseBindingOpts
+	assert.NoError(t, g.SetViewClickBinding(&ViewMouseBinding{
+		ViewName: "v",
+		Key:      MouseLeft,
+		Handler: func(opts ViewMouseBindingOpts) error {
+			clicked = opts
+			return nil
+		},
+	}))
+
+	// the gutter is 2 cells wide, so the content starts at x=3
+	assert.NoError(t, g.onKey(&GocuiEvent{Type: eventMouse, Key: MouseLeft, MouseX: 5, MouseY: 2}))
+	assert.Equal(t, 2, clicked.X)
+	assert.Equal(t, 1, clicked.Y)
+	assert.Equal(t, 2, v.CursorX())
+
+	// clicking on a line number goes to the start of the line
+	assert.NoError(t, g.onKey(&GocuiEvent{Type: eventMouse, Key: MouseLeft, MouseX: 1, MouseY: 1}))
+	assert.Equal(t, 0, clicked.X)
+	assert.Equal(t, 0, clicked.Y)
+}
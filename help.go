@@ -0,0 +1,222 @@
+package gocui
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-errors/errors"
+	"github.com/rivo/uniseg"
+)
+
+// KeybindingHelpViewName is the name of the view created by ShowKeybindingHelp.
+const KeybindingHelpViewName = "keybindingHelp"
+
+// KeybindingInfo describes a keybinding, e.g. for rendering a help screen.
+type KeybindingInfo struct {
+	// the view the keybinding applies to; empty for global keybindings
+	ViewName    string
+	Key         Key
+	Ch          rune
+	Mod         Modifier
+	Description string
+	Category    string
+}
+
+// Label returns a human-readable representation of the key combination, like
+// "Ctrl+A" or "Alt+x". It's the same as Format, but with the modifiers and key
+// names capitalized.
+func (kb KeybindingInfo) Label() string {
+	var key any = kb.Key
+	if kb.Ch != 0 {
+		key = kb.Ch
+	}
+
+	label := Format(key, kb.Mod)
+	if kb.Ch != 0 {
+		// leave the rune itself alone, since its case matters
+		modifiers := strings.TrimSuffix(label, string(kb.Ch))
+		return capitalizeKeyName(modifiers) + string(kb.Ch)
+	}
+	return capitalizeKeyName(label)
+}
+
+func capitalizeKeyName(name string) string {
+	tokens := strings.Split(name, "+")
+	for i, token := range tokens {
+		if token == "" {
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(token)
+		tokens[i] = string(unicode.ToUpper(r)) + token[size:]
+	}
+	return strings.Join(tokens, "+")
+}
+
+// ActiveKeybindings returns the keybindings that apply to the current view:
+// first those of the view itself, then those of its parent view, then the
+// global ones. Keybindings that are shadowed by a more specific keybinding for
+// the same key are omitted.
+func (g *Gui) ActiveKeybindings() []KeybindingInfo {
+	type keyCombo struct {
+		key Key
+		ch  rune
+		mod Modifier
+	}
+
+	v := g.currentView
+	scopes := []*View{v}
+	if v != nil && v.ParentView != nil {
+		scopes = append(scopes, v.ParentView)
+	}
+	scopes = append(scopes, nil)
+
+	seen := map[keyCombo]bool{}
+	var result []KeybindingInfo
+	for _, scope := range scopes {
+		for _, kb := range g.keybindings {
+			if kb.handler == nil || g.isBlacklisted(kb.key) {
+				continue
+			}
+			if scope == nil {
+				if kb.viewName != "" {
+					continue
+				}
+			} else if !g.matchView(scope, kb) {
+				continue
+			}
+
+			combo := keyCombo{key: kb.key, ch: kb.ch, mod: kb.mod}
+			if seen[combo] {
+				continue
+			}
+			seen[combo] = true
+
+			result = append(result, KeybindingInfo{
+				ViewName:    kb.viewName,
+				Key:         kb.key,
+				Ch:          kb.ch,
+				Mod:         kb.mod,
+				Description: kb.description,
+				Category:    kb.category,
+			})
+		}
+	}
+
+	return result
+}
+
+// FormatKeybindingHelp renders the given keybindings as a table of key labels
+// and descriptions, grouped by category in the order in which the categories
+// first appear. Keybindings without a description are left out.
+func FormatKeybindingHelp(bindings []KeybindingInfo) string {
+	var categories []string
+	byCategory := map[string][]KeybindingInfo{}
+	labelWidth := 0
+	for _, kb := range bindings {
+		if kb.Description == "" {
+			continue
+		}
+		category := kb.Category
+		if category == "" {
+			category = "General"
+		}
+		if !slices.Contains(categories, category) {
+			categories = append(categories, category)
+		}
+		byCategory[category] = append(byCategory[category], kb)
+		labelWidth = max(labelWidth, uniseg.StringWidth(kb.Label()))
+	}
+
+	var b strings.Builder
+	for i, category := range categories {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(category + "\n")
+		for _, kb := range byCategory[category] {
+			label := kb.Label()
+			b.WriteString("  " + label)
+			b.WriteString(strings.Repeat(" ", labelWidth-uniseg.StringWidth(label)+2))
+			b.WriteString(kb.Description + "\n")
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// ShowKeybindingHelp opens a popup in the middle of the screen that lists the
+// active keybindings of the current view (see ActiveKeybindings). Pressing Esc
+// or q closes the popup and gives the focus back to the previously focused
+// view.
+func (g *Gui) ShowKeybindingHelp() error {
+	content := FormatKeybindingHelp(g.ActiveKeybindings())
+	lines := strings.Split(content, "\n")
+	contentWidth := 0
+	for _, line := range lines {
+		contentWidth = max(contentWidth, uniseg.StringWidth(line))
+	}
+
+	width := min(contentWidth+2, g.maxX)
+	height := min(len(lines)+2, g.maxY)
+	x0 := (g.maxX - width) / 2
+	y0 := (g.maxY - height) / 2
+
+	previousView := g.currentView
+	v, err := g.SetView(KeybindingHelpViewName, x0, y0, x0+width-1, y0+height-1, 0)
+	if err != nil && !errors.Is(err, ErrUnknownView) {
+		return err
+	}
+	v.Title = "Keybindings"
+	v.SetContent(content)
+
+	if _, err := g.SetViewOnTop(KeybindingHelpViewName); err != nil {
+		return err
+	}
+	if _, err := g.SetCurrentView(KeybindingHelpViewName); err != nil {
+		return err
+	}
+
+	closeHelp := func(g *Gui, _ *View) error {
+		g.DeleteViewKeybindings(KeybindingHelpViewName)
+		if err := g.DeleteView(KeybindingHelpViewName); err != nil {
+			return err
+		}
+		if previousView != nil {
+			if _, err := g.SetCurrentView(previousView.Name()); err != nil && !errors.Is(err, ErrUnknownView) {
+				return err
+			}
+		}
+		return nil
+	}
+	scroll := func(amount int) func(*Gui, *View) error {
+		return func(_ *Gui, v *View) error {
+			if amount < 0 {
+				v.ScrollUp(-amount)
+			} else {
+				v.ScrollDown(amount)
+			}
+			return nil
+		}
+	}
+
+	g.DeleteViewKeybindings(KeybindingHelpViewName)
+	for _, binding := range []struct {
+		key     any
+		handler func(*Gui, *View) error
+	}{
+		{KeyEsc, closeHelp},
+		{'q', closeHelp},
+		{KeyArrowDown, scroll(1)},
+		{KeyArrowUp, scroll(-1)},
+		{KeyPgdn, scroll(height - 2)},
+		{KeyPgup, scroll(-(height - 2))},
+	} {
+		if err := g.SetKeybinding(KeybindingHelpViewName, binding.key, ModNone, binding.handler); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
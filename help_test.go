@@ -0,0 +1,64 @@
+package gocui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActiveKeybindings(t *testing.T) {
+	noop := func(*Gui, *View) error { return nil }
+
+	g := &Gui{}
+	parent := NewView("parent", 0, 0, 10, 10, OutputNormal)
+	child := NewView("child", 0, 0, 10, 10, OutputNormal)
+	child.ParentView = parent
+	g.views = []*View{parent, child}
+	g.currentView = child
+
+	assert.NoError(t, g.SetDescribedKeybinding("", 'q', ModNone, "Quit", "", noop))
+	assert.NoError(t, g.SetDescribedKeybinding("", KeyEnter, ModNone, "Global enter", "", noop))
+	assert.NoError(t, g.SetDescribedKeybinding("parent", 'x', ModNone, "Remove", "Files", noop))
+	assert.NoError(t, g.SetDescribedKeybinding("child", KeyEnter, ModNone, "Open", "Files", noop))
+	assert.NoError(t, g.SetDescribedKeybinding("child", 'a', ModAlt, "Amend", "Commits", noop))
+	assert.NoError(t, g.SetDescribedKeybinding("other", 'z', ModNone, "Other", "Other", noop))
+
+	bindings := g.ActiveKeybindings()
+	descriptions := make([]string, 0, len(bindings))
+	for _, kb := range bindings {
+		descriptions = append(descriptions, kb.Description)
+	}
+	assert.Equal(t, []string{"Open", "Amend", "Remove", "Quit"}, descriptions)
+
+	expected := "" +
+		"Files\n" +
+		"  Enter  Open\n" +
+		"  x      Remove\n" +
+		"\n" +
+		"Commits\n" +
+		"  Alt+a  Amend\n" +
+		"\n" +
+		"General\n" +
+		"  q      Quit"
+	assert.Equal(t, expected, FormatKeybindingHelp(bindings))
+}
+
+func TestKeybindingLabel(t *testing.T) {
+	tests := []struct {
+		kb       KeybindingInfo
+		expected string
+	}{
+		{KeybindingInfo{Ch: 'a'}, "a"},
+		{KeybindingInfo{Ch: 'a', Mod: ModAlt}, "Alt+a"},
+		{KeybindingInfo{Key: KeyCtrlA}, "Ctrl+A"},
+		{KeybindingInfo{Key: KeyCtrlK, Mod: ModAlt}, "Ctrl+Alt+K"},
+		{KeybindingInfo{Key: KeyEsc}, "Esc"},
+		{KeybindingInfo{Key: KeyArrowUp, Mod: ModShift}, "Shift+Up"},
+		{KeybindingInfo{Key: MouseLeft}, "Mouseleft"},
+		{KeybindingInfo{Key: KeyPgdn}, "Pgdn"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.kb.Label())
+	}
+}
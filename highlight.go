@@ -0,0 +1,159 @@
+package gocui
+
+import (
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// HighlightSpan assigns colors to a part of the content of a TextArea, given
+// as byte offsets into the content.
+type HighlightSpan struct {
+	Start, End       int
+	FgColor, BgColor Attribute
+}
+
+// Highlighter interface must be satisfied by syntax highlighters for editable
+// views. Highlight is called with the unwrapped content of the text area and
+// returns the spans to style. If spans overlap, later ones win.
+type Highlighter interface {
+	Highlight(content string) []HighlightSpan
+}
+
+// The HighlighterFunc type is an adapter to allow the use of ordinary
+// functions as Highlighters.
+type HighlighterFunc func(content string) []HighlightSpan
+
+// Highlight calls f(content)
+func (f HighlighterFunc) Highlight(content string) []HighlightSpan {
+	return f(content)
+}
+
+// CommitMessageHighlighter highlights the part of a commit message's subject
+// line that exceeds the subject limit, and comment lines.
+type CommitMessageHighlighter struct {
+	// SubjectLimit is the maximum number of characters of the subject line
+	// (default 50).
+	SubjectLimit int
+	// CommentChar starts a comment line (default "#").
+	CommentChar string
+	// OverflowColor is used for the part of the subject that is too long
+	// (default ColorRed).
+	OverflowColor Attribute
+	// CommentColor is used for comment lines (default AttrDim).
+	CommentColor Attribute
+}
+
+// Highlight implements Highlighter.
+func (h CommitMessageHighlighter) Highlight(content string) []HighlightSpan {
+	subjectLimit := h.SubjectLimit
+	if subjectLimit <= 0 {
+		subjectLimit = 50
+	}
+	commentChar := h.CommentChar
+	if commentChar == "" {
+		commentChar = "#"
+	}
+	overflowColor := h.OverflowColor
+	if overflowColor == ColorDefault {
+		overflowColor = ColorRed
+	}
+	commentColor := h.CommentColor
+	if commentColor == ColorDefault {
+		commentColor = AttrDim
+	}
+
+	var spans []HighlightSpan
+	seenSubject := false
+	lineStart := 0
+	for _, line := range strings.SplitAfter(content, "\n") {
+		text := strings.TrimSuffix(line, "\n")
+		lineEnd := lineStart + len(text)
+
+		if strings.HasPrefix(text, commentChar) {
+			spans = append(spans, HighlightSpan{Start: lineStart, End: lineEnd, FgColor: commentColor, BgColor: ColorDefault})
+		} else if !seenSubject {
+			seenSubject = true
+			if overflow := graphemeOffset(text, subjectLimit); overflow < len(text) {
+				spans = append(spans, HighlightSpan{Start: lineStart + overflow, End: lineEnd, FgColor: overflowColor, BgColor: ColorDefault})
+			}
+		}
+
+		lineStart += len(line)
+	}
+
+	return spans
+}
+
+// graphemeOffset returns the byte offset of the grapheme cluster with the
+// given index in str, or len(str) if str is shorter.
+func graphemeOffset(str string, index int) int {
+	offset := 0
+	state := -1
+	for i := 0; i < index && offset < len(str); i++ {
+		var cluster string
+		cluster, _, _, state = uniseg.FirstGraphemeClusterInString(str[offset:], state)
+		offset += len(cluster)
+	}
+	return offset
+}
+
+// textAreaSelector is implemented by editors that can have a selection in the
+// text area, like VimEditor in visual mode.
+type textAreaSelector interface {
+	textAreaSelection(t *TextArea) (start int, end int, ok bool)
+}
+
+// textAreaSelection returns the range of the text area that the view's
+// editor has selected, if any.
+func (v *View) textAreaSelection() (int, int, bool) {
+	if selector, ok := v.Editor.(textAreaSelector); ok {
+		return selector.textAreaSelection(v.TextArea)
+	}
+	return 0, 0, false
+}
+
+// writeHighlightedTextArea writes the content of the text area to the view,
+// styled by the view's highlighter (if any) and with the editor's selection
+// shown in reverse video. Styling doesn't change which cells are written, so
+// cursor positions and soft line breaks are unaffected.
+func (v *View) writeHighlightedTextArea() {
+	var spans []HighlightSpan
+	if v.Highlighter != nil {
+		spans = v.Highlighter.Highlight(v.TextArea.GetUnwrappedContent())
+	}
+	selectionStart, selectionEnd, _ := v.textAreaSelection()
+	styleAt := func(contentIndex int) (Attribute, Attribute) {
+		fgColor, bgColor := ColorDefault, ColorDefault
+		for _, span := range spans {
+			if contentIndex >= span.Start && contentIndex < span.End {
+				fgColor, bgColor = span.FgColor, span.BgColor
+			}
+		}
+		if contentIndex >= selectionStart && contentIndex < selectionEnd {
+			fgColor |= AttrReverse
+		}
+		return fgColor, bgColor
+	}
+
+	v.writeMutex.Lock()
+	defer v.writeMutex.Unlock()
+
+	var run strings.Builder
+	var runFgColor, runBgColor Attribute
+	flush := func() {
+		v.ei.curFgColor, v.ei.curBgColor = runFgColor, runBgColor
+		v.writeWithMarkup([]byte(run.String()), false)
+		run.Reset()
+	}
+	for _, c := range v.TextArea.cells {
+		fgColor, bgColor := styleAt(c.contentIndex)
+		if run.Len() > 0 && (fgColor != runFgColor || bgColor != runBgColor) {
+			flush()
+		}
+		runFgColor, runBgColor = fgColor, bgColor
+		run.WriteString(c.char)
+	}
+	flush()
+	v.ei.curFgColor, v.ei.curBgColor = ColorDefault, ColorDefault
+}
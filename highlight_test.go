@@ -0,0 +1,69 @@
+package gocui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitMessageHighlighter(t *testing.T) {
+	h := CommitMessageHighlighter{SubjectLimit: 5}
+	content := "# comment\nsubjéct line\n\nbody line that is long\n# another"
+	assert.Equal(t, []HighlightSpan{
+		{Start: 0, End: 9, FgColor: AttrDim, BgColor: ColorDefault},
+		{Start: 16, End: 23, FgColor: ColorRed, BgColor: ColorDefault},
+		{Start: 48, End: 57, FgColor: AttrDim, BgColor: ColorDefault},
+	}, h.Highlight(content))
+	assert.Equal(t, "t line", content[17:23])
+
+	assert.Empty(t, h.Highlight("short"))
+}
+
+func TestRenderTextAreaWithHighlighter(t *testing.T) {
+	render := func(highlighter Highlighter) *View {
+		v := NewView("v", 0, 0, 11, 5, OutputNormal)
+		v.Editable = true
+		v.TextArea.AutoWrap = true
+		v.TextArea.AutoWrapWidth = 10
+		v.Highlighter = highlighter
+		v.TextArea.TypeString("subject that is long\n# comment")
+		v.TextArea.SetCursor2D(3, 1)
+		v.RenderTextArea()
+		return v
+	}
+
+	plain := render(nil)
+	highlighted := render(CommitMessageHighlighter{SubjectLimit: 10})
+
+	assert.Equal(t, plain.Buffer(), highlighted.Buffer())
+	assert.Equal(t, plain.lines[0][0].fgColor, ColorDefault)
+	plainCx, plainCy := plain.Cursor()
+	cx, cy := highlighted.Cursor()
+	assert.Equal(t, []int{plainCx, plainCy}, []int{cx, cy})
+
+	colors := func(line []cell) string {
+		var b strings.Builder
+		for _, c := range line {
+			if c.chr == "" {
+				continue
+			}
+			switch c.fgColor {
+			case ColorRed:
+				b.WriteString("r")
+			case AttrDim:
+				b.WriteString("d")
+			default:
+				b.WriteString(".")
+			}
+		}
+		return b.String()
+	}
+	// the subject is soft-wrapped into "subject ", "that is " and "long"; the
+	// overflow starts at the 11th character
+	var lineColors []string
+	for _, line := range highlighted.lines {
+		lineColors = append(lineColors, colors(line))
+	}
+	assert.Equal(t, []string{"........", "..rrrrrr", "rrrr", "ddddddddd"}, lineColors)
+}
@@ -0,0 +1,284 @@
+package gocui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/go-errors/errors"
+)
+
+// DefaultMaxHistoryEntries is the number of entries an InputHistory keeps per
+// prompt if no other limit is given.
+const DefaultMaxHistoryEntries = 100
+
+// InputHistory stores the entries that were submitted in prompts, separately
+// for each named prompt, optionally persisting them to a file. Views use it
+// via View.History: Up and Down cycle through previous entries, and Ctrl+R
+// searches them.
+type InputHistory struct {
+	maxEntries int
+	path       string
+	entries    map[string][]string
+	mutex      sync.Mutex
+}
+
+// NewInputHistory returns an in-memory history that keeps at most maxEntries
+// entries per prompt (DefaultMaxHistoryEntries if maxEntries is 0).
+func NewInputHistory(maxEntries int) *InputHistory {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxHistoryEntries
+	}
+
+	return &InputHistory{maxEntries: maxEntries, entries: map[string][]string{}}
+}
+
+// LoadInputHistory returns a history that is persisted in the file at the
+// given path. The file is read if it exists, and written whenever an entry is
+// added.
+func LoadInputHistory(path string, maxEntries int) (*InputHistory, error) {
+	h := NewInputHistory(maxEntries)
+	h.path = path
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return h, nil
+		}
+		return nil, errors.Wrap(err, 0)
+	}
+	if err := json.Unmarshal(data, &h.entries); err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	if h.entries == nil {
+		h.entries = map[string][]string{}
+	}
+	for prompt, entries := range h.entries {
+		h.entries[prompt] = h.cap(entries)
+	}
+
+	return h, nil
+}
+
+// Entries returns the entries of the given prompt, oldest first.
+func (h *InputHistory) Entries(prompt string) []string {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return slices.Clone(h.entries[prompt])
+}
+
+// Add adds an entry to the history of the given prompt. Empty entries are
+// ignored; if the entry is already in the history, it's moved to the end.
+func (h *InputHistory) Add(prompt string, entry string) error {
+	if strings.TrimSpace(entry) == "" {
+		return nil
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	entries := slices.DeleteFunc(h.entries[prompt], func(e string) bool { return e == entry })
+	h.entries[prompt] = h.cap(append(entries, entry))
+
+	return h.save()
+}
+
+func (h *InputHistory) cap(entries []string) []string {
+	if len(entries) > h.maxEntries {
+		return entries[len(entries)-h.maxEntries:]
+	}
+	return entries
+}
+
+func (h *InputHistory) save() error {
+	if h.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(h.entries)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return errors.Wrap(err, 0)
+	}
+	if err := os.WriteFile(h.path, data, 0o644); err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return nil
+}
+
+// historyState is the state of a view's navigation through its history.
+type historyState struct {
+	// the index of the entry that is shown; len(entries) means the draft
+	index int
+	// the content of the entries that the user edited before moving on to
+	// another one, by index; len(entries) is the content the user was typing
+	// before navigating the history
+	edits map[int]string
+
+	searching bool
+	query     string
+	// the content and subtitle before the search started, for cancelling it
+	contentBeforeSearch  string
+	subtitleBeforeSearch string
+}
+
+func (v *View) historyName() string {
+	if v.HistoryName != "" {
+		return v.HistoryName
+	}
+	return v.name
+}
+
+// AddToHistory adds the current content of the view's text area to its
+// history, and resets the navigation through the history. Call this when the
+// prompt is submitted.
+func (v *View) AddToHistory() error {
+	v.historyState = nil
+	if v.History == nil {
+		return nil
+	}
+
+	return v.History.Add(v.historyName(), v.TextArea.GetUnwrappedContent())
+}
+
+func (v *View) setTextAreaContent(content string) {
+	v.TextArea.Clear()
+	v.TextArea.TypeString(content)
+	v.RenderTextArea()
+}
+
+// execHistoryKeybindings handles the keys for navigating and searching the
+// history of a single-line editable view. Returns true if the key was
+// handled.
+func (g *Gui) execHistoryKeybindings(v *View, ev *GocuiEvent) (bool, error) {
+	if v == nil || !v.Editable || v.History == nil || !v.TextArea.SingleLine {
+		return false, nil
+	}
+
+	entries := v.History.Entries(v.historyName())
+	if v.historyState == nil {
+		v.historyState = &historyState{index: len(entries), edits: map[int]string{}}
+	}
+	state := v.historyState
+	state.index = min(state.index, len(entries))
+
+	if state.searching {
+		return v.execHistorySearchKey(entries, ev), nil
+	}
+
+	if ev.Ch != 0 || ev.Mod != ModNone {
+		return false, nil
+	}
+
+	// shows the entry with the given index, keeping the edits of the one
+	// that is shown now
+	showEntry := func(index int) {
+		content := v.TextArea.GetUnwrappedContent()
+		if state.index == len(entries) || content != entries[state.index] {
+			state.edits[state.index] = content
+		} else {
+			delete(state.edits, state.index)
+		}
+
+		state.index = index
+		content, edited := state.edits[index]
+		if !edited && index < len(entries) {
+			content = entries[index]
+		}
+		v.setTextAreaContent(content)
+	}
+
+	switch ev.Key {
+	case KeyArrowUp:
+		if state.index == 0 {
+			return true, nil
+		}
+		showEntry(state.index - 1)
+	case KeyArrowDown:
+		if state.index == len(entries) {
+			return true, nil
+		}
+		showEntry(state.index + 1)
+	case KeyCtrlR:
+		state.searching = true
+		state.query = ""
+		state.contentBeforeSearch = v.TextArea.GetUnwrappedContent()
+		state.subtitleBeforeSearch = v.Subtitle
+		v.showHistorySearchStatus(false)
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// execHistorySearchKey handles a key during a reverse incremental search:
+// runes extend the query, Backspace shortens it, Ctrl+R finds the next older
+// match, and Esc cancels the search. Any other key accepts the match and is
+// then handled as usual, so we return false for it.
+func (v *View) execHistorySearchKey(entries []string, ev *GocuiEvent) bool {
+	state := v.historyState
+
+	switch {
+	case ev.Ch != 0 || ev.Key == KeySpace:
+		if ev.Ch != 0 {
+			state.query += string(ev.Ch)
+		} else {
+			state.query += " "
+		}
+		v.searchHistory(entries, min(state.index, len(entries)-1))
+	case ev.Key == KeyBackspace || ev.Key == KeyBackspace2:
+		if state.query != "" {
+			_, size := utf8.DecodeLastRuneInString(state.query)
+			state.query = state.query[:len(state.query)-size]
+		}
+		v.searchHistory(entries, len(entries)-1)
+	case ev.Key == KeyCtrlR:
+		v.searchHistory(entries, state.index-1)
+	case ev.Key == KeyEsc:
+		v.stopHistorySearch()
+		state.index = len(entries)
+		v.setTextAreaContent(state.contentBeforeSearch)
+	default:
+		v.stopHistorySearch()
+		return false
+	}
+
+	return true
+}
+
+// searchHistory shows the newest entry at or before the given index that
+// contains the query.
+func (v *View) searchHistory(entries []string, from int) {
+	state := v.historyState
+	for i := from; i >= 0; i-- {
+		if strings.Contains(entries[i], state.query) {
+			state.index = i
+			v.setTextAreaContent(entries[i])
+			v.showHistorySearchStatus(false)
+			return
+		}
+	}
+
+	v.showHistorySearchStatus(true)
+}
+
+func (v *View) showHistorySearchStatus(failed bool) {
+	prefix := "reverse-i-search"
+	if failed {
+		prefix = "failed " + prefix
+	}
+	v.Subtitle = "(" + prefix + ")`" + v.historyState.query + "'"
+}
+
+func (v *View) stopHistorySearch() {
+	v.historyState.searching = false
+	v.Subtitle = v.historyState.subtitleBeforeSearch
+}
@@ -0,0 +1,111 @@
+package gocui
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInputHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	history, err := LoadInputHistory(path, 3)
+	assert.NoError(t, err)
+
+	for _, entry := range []string{"a", "b", "", "c", "a", "d"} {
+		assert.NoError(t, history.Add("search", entry))
+	}
+	assert.NoError(t, history.Add("commands", "x"))
+
+	// capped and de-duplicated
+	assert.Equal(t, []string{"c", "a", "d"}, history.Entries("search"))
+	assert.Equal(t, []string{"x"}, history.Entries("commands"))
+
+	loaded, err := LoadInputHistory(path, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "d"}, loaded.Entries("search"))
+	assert.Equal(t, []string{"x"}, loaded.Entries("commands"))
+}
+
+func TestHistoryNavigation(t *testing.T) {
+	g := &Gui{}
+	v := NewView("prompt", 0, 0, 30, 2, OutputNormal)
+	v.Editable = true
+	v.TextArea.SingleLine = true
+	v.Subtitle = "subtitle"
+	v.History = NewInputHistory(0)
+	g.currentView = v
+
+	for _, entry := range []string{"git status", "git log", "ls -la"} {
+		v.setTextAreaContent(entry)
+		assert.NoError(t, v.AddToHistory())
+	}
+	v.setTextAreaContent("dra")
+
+	press := func(ev *GocuiEvent) {
+		assert.NoError(t, g.execKeybindings(v, ev))
+	}
+	key := func(key Key) *GocuiEvent { return &GocuiEvent{Type: eventKey, Key: key} }
+	content := func() string { return v.TextArea.GetUnwrappedContent() }
+
+	press(key(KeyArrowUp))
+	assert.Equal(t, "ls -la", content())
+	press(key(KeyArrowUp))
+	press(key(KeyArrowUp))
+	press(key(KeyArrowUp))
+	assert.Equal(t, "git status", content())
+	press(key(KeyArrowDown))
+	assert.Equal(t, "git log", content())
+	press(key(KeyArrowDown))
+	press(key(KeyArrowDown))
+	assert.Equal(t, "dra", content())
+	press(key(KeyArrowDown))
+	assert.Equal(t, "dra", content())
+
+	// reverse incremental search
+	press(key(KeyCtrlR))
+	assert.Equal(t, "(reverse-i-search)`'", v.Subtitle)
+	press(&GocuiEvent{Type: eventKey, Ch: 'g'})
+	assert.Equal(t, "git log", content())
+	press(key(KeyCtrlR))
+	assert.Equal(t, "git status", content())
+	press(&GocuiEvent{Type: eventKey, Ch: 'x'})
+	assert.Equal(t, "(failed reverse-i-search)`gx'", v.Subtitle)
+	press(key(KeyBackspace))
+	assert.Equal(t, "(reverse-i-search)`g'", v.Subtitle)
+	assert.Equal(t, "git log", content())
+	press(key(KeyEsc))
+	assert.Equal(t, "dra", content())
+	assert.Equal(t, "subtitle", v.Subtitle)
+
+	// any other key accepts the match and is handled as usual
+	press(key(KeyCtrlR))
+	press(&GocuiEvent{Type: eventKey, Ch: 's'})
+	press(key(KeyCtrlR))
+	assert.Equal(t, "git status", content())
+	press(key(KeyEnd))
+	press(&GocuiEvent{Type: eventKey, Ch: '!'})
+	assert.Equal(t, "git status!", content())
+	assert.Equal(t, "subtitle", v.Subtitle)
+
+	// edits of recalled entries are kept while navigating
+	press(key(KeyArrowDown))
+	assert.Equal(t, "git log", content())
+	press(key(KeyArrowUp))
+	assert.Equal(t, "git status!", content())
+	press(key(KeyArrowDown))
+	press(key(KeyArrowDown))
+	press(key(KeyArrowDown))
+	assert.Equal(t, "dra", content())
+	assert.Equal(t, []string{"git status", "git log", "ls -la"}, v.History.Entries("prompt"))
+
+	// keybindings of the view take precedence over the history keys
+	called := false
+	assert.NoError(t, g.SetKeybinding("prompt", KeyArrowUp, ModNone, func(*Gui, *View) error {
+		called = true
+		return nil
+	}))
+	press(key(KeyArrowUp))
+	assert.True(t, called)
+	assert.Equal(t, "dra", content())
+}
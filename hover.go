@@ -0,0 +1,135 @@
+package gocui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/rivo/uniseg"
+)
+
+// TooltipViewName is the name of the view that shows tooltips.
+const TooltipViewName = "tooltip"
+
+// onMouseMove is called whenever the mouse pointer moves without a button
+// being pressed. v is the view under the pointer, or nil.
+func (g *Gui) onMouseMove(v *View, mx, my int) {
+	if g.lastHoverView != v {
+		if previous := g.lastHoverView; previous != nil {
+			previous.lastHoverPosition = nil
+			previous.hoveredHyperlink = nil
+			if previous.OnMouseLeave != nil {
+				previous.OnMouseLeave()
+			}
+		}
+		g.lastHoverView = v
+		if v != nil && v.OnMouseEnter != nil {
+			v.OnMouseEnter()
+		}
+	}
+
+	if v == nil {
+		return
+	}
+
+	v.onMouseMove(mx, my)
+
+	cx, cy := mx-v.contentX0(), my-v.contentY0()
+	width, height := v.InnerSize()
+	if cx < 0 || cx >= width || cy < 0 || cy >= height {
+		return
+	}
+
+	y := cy + v.oy
+	x := v.visualToLogicalX(cx+v.ox, y)
+	if v.OnMouseHover != nil {
+		v.OnMouseHover(x, y)
+	}
+	g.scheduleTooltip(v, x, y, mx, my)
+}
+
+// pendingTooltip is a tooltip that is waiting for the tooltip timer to fire.
+type pendingTooltip struct {
+	text   string
+	mx, my int
+}
+
+// scheduleTooltip shows the tooltip of the given view for the given content
+// position after TooltipDelay, unless the mouse moves in the meantime.
+func (g *Gui) scheduleTooltip(v *View, x, y, mx, my int) {
+	tooltip := v.Tooltip
+	if v.TooltipFunc != nil {
+		tooltip = v.TooltipFunc(x, y)
+	}
+	if tooltip == "" {
+		return
+	}
+
+	g.pendingTooltip = &pendingTooltip{text: tooltip, mx: mx, my: my}
+	if g.tooltipTimer == nil {
+		g.tooltipTimer = time.AfterFunc(g.TooltipDelay, func() {
+			g.Update(func(g *Gui) error {
+				return g.showPendingTooltip()
+			})
+		})
+		return
+	}
+	g.tooltipTimer.Reset(g.TooltipDelay)
+}
+
+// showPendingTooltip shows the pending tooltip, if it hasn't been cancelled
+// since the tooltip timer fired.
+func (g *Gui) showPendingTooltip() error {
+	tooltip := g.pendingTooltip
+	if tooltip == nil {
+		return nil
+	}
+
+	g.pendingTooltip = nil
+	return g.showTooltip(tooltip.text, tooltip.mx, tooltip.my)
+}
+
+// showTooltip shows the given text in a floating view below and to the right
+// of the given screen position, moving it so that it stays on screen.
+func (g *Gui) showTooltip(text string, mx, my int) error {
+	lines := strings.Split(text, "\n")
+	contentWidth := 0
+	for _, line := range lines {
+		contentWidth = max(contentWidth, uniseg.StringWidth(line))
+	}
+
+	width := min(contentWidth+2, g.maxX)
+	height := min(len(lines)+2, g.maxY)
+	x0, y0 := mx+1, my+1
+	if x0+width > g.maxX {
+		x0 = max(0, g.maxX-width)
+	}
+	if y0+height > g.maxY {
+		y0 = max(0, my-height)
+	}
+
+	v, err := g.SetView(TooltipViewName, x0, y0, x0+width-1, y0+height-1, 0)
+	if err != nil && !errors.Is(err, ErrUnknownView) {
+		return err
+	}
+	v.SetContent(text)
+	g.tooltipVisible = true
+
+	_, err = g.SetViewOnTop(TooltipViewName)
+	return err
+}
+
+// hideTooltip hides the tooltip if it is shown, and cancels any pending
+// tooltip.
+func (g *Gui) hideTooltip() {
+	g.pendingTooltip = nil
+	if g.tooltipTimer != nil {
+		g.tooltipTimer.Stop()
+	}
+	if !g.tooltipVisible {
+		return
+	}
+
+	g.tooltipVisible = false
+	_ = g.DeleteView(TooltipViewName)
+}
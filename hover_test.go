@@ -0,0 +1,91 @@
+package gocui
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMouseHoverCallbacks(t *testing.T) {
+	g := &Gui{}
+	first := NewView("first", 0, 0, 10, 5, OutputNormal)
+	second := NewView("second", 20, 0, 30, 5, OutputNormal)
+	second.oy = 2
+	g.views = []*View{first, second}
+
+	var events []string
+	for _, v := range g.views {
+		v.OnMouseEnter = func() { events = append(events, "enter "+v.Name()) }
+		v.OnMouseLeave = func() { events = append(events, "leave "+v.Name()) }
+		v.OnMouseHover = func(x, y int) { events = append(events, fmt.Sprintf("hover %s %d,%d", v.Name(), x, y)) }
+	}
+
+	g.onMouseMove(first, 2, 1)
+	g.onMouseMove(first, 3, 1)
+	// on the frame: no hover
+	g.onMouseMove(first, 0, 1)
+	g.onMouseMove(second, 22, 3)
+	g.onMouseMove(nil, 15, 3)
+
+	assert.Equal(t, []string{
+		"enter first",
+		"hover first 1,0",
+		"hover first 2,0",
+		"leave first",
+		"enter second",
+		"hover second 1,4",
+		"leave second",
+	}, events)
+}
+
+func TestShowTooltip(t *testing.T) {
+	g := &Gui{maxX: 20, maxY: 10}
+
+	assert.NoError(t, g.showTooltip("hello", 2, 3))
+	v, err := g.View(TooltipViewName)
+	assert.NoError(t, err)
+	x0, y0, x1, y1 := v.Dimensions()
+	assert.Equal(t, []int{3, 4, 9, 6}, []int{x0, y0, x1, y1})
+	assert.Equal(t, "hello", v.Buffer())
+
+	// near the bottom right corner the tooltip is moved to stay on screen
+	assert.NoError(t, g.showTooltip("hello\nworld", 18, 8))
+	x0, y0, x1, y1 = v.Dimensions()
+	assert.Equal(t, []int{13, 4, 19, 7}, []int{x0, y0, x1, y1})
+
+	g.hideTooltip()
+	_, err = g.View(TooltipViewName)
+	assert.True(t, errors.Is(err, ErrUnknownView))
+}
+
+func TestScheduleTooltip(t *testing.T) {
+	g := &Gui{maxX: 20, maxY: 10, TooltipDelay: time.Hour}
+	v := NewView("v", 0, 0, 10, 5, OutputNormal)
+	v.Tooltip = "hello"
+
+	// moving the mouse reuses the pending timer instead of starting a new one
+	g.scheduleTooltip(v, 1, 1, 2, 2)
+	timer := g.tooltipTimer
+	g.scheduleTooltip(v, 2, 1, 3, 2)
+	assert.Same(t, timer, g.tooltipTimer)
+	assert.Equal(t, &pendingTooltip{text: "hello", mx: 3, my: 2}, g.pendingTooltip)
+
+	assert.NoError(t, g.showPendingTooltip())
+	assert.True(t, g.tooltipVisible)
+
+	// a key press hides the tooltip and cancels a pending one
+	g.scheduleTooltip(v, 2, 1, 3, 2)
+	assert.NoError(t, g.onKey(&GocuiEvent{Type: eventKey, Key: KeyCtrlA}))
+	assert.False(t, g.tooltipVisible)
+	assert.Nil(t, g.pendingTooltip)
+	_, err := g.View(TooltipViewName)
+	assert.True(t, errors.Is(err, ErrUnknownView))
+
+	// a timer that fired before being cancelled doesn't show anything
+	assert.NoError(t, g.showPendingTooltip())
+	assert.False(t, g.tooltipVisible)
+	g.tooltipTimer.Stop()
+}
@@ -0,0 +1,159 @@
+package gocui
+
+// hyperlinkRange is the part of a view covered by a hyperlink, in view lines
+// and columns. A hyperlink spans several view lines if it is wrapped.
+type hyperlinkRange struct {
+	link   string
+	startY int
+	startX int
+	endY   int
+	// endX is the column after the end of the link on line endY
+	endX int
+}
+
+func (r hyperlinkRange) contains(x, y int) bool {
+	if y < r.startY || y > r.endY {
+		return false
+	}
+	return (y > r.startY || x >= r.startX) && (y < r.endY || x < r.endX)
+}
+
+// hyperlinkRanges returns the hyperlinks in the view's lines, in the order in
+// which they appear.
+func (v *View) hyperlinkRanges() []hyperlinkRange {
+	v.refreshViewLinesIfNeeded()
+
+	var ranges []hyperlinkRange
+	// the index of the range that the previous cell belonged to, or -1
+	current := -1
+	for y, vline := range v.viewLines {
+		if vline.linesX == 0 {
+			current = -1
+		}
+		x := 0
+		for _, c := range vline.line {
+			switch {
+			case c.hyperlink == "":
+				current = -1
+			case current >= 0 && ranges[current].link == c.hyperlink:
+				ranges[current].endY = y
+				ranges[current].endX = x + c.width
+			default:
+				ranges = append(ranges, hyperlinkRange{
+					link: c.hyperlink, startY: y, startX: x, endY: y, endX: x + c.width,
+				})
+				current = len(ranges) - 1
+			}
+			x += c.width
+		}
+	}
+	return ranges
+}
+
+// FocusNextHyperlink moves the keyboard focus to the next hyperlink in the
+// view, or to the first visible one if no hyperlink has the focus, and
+// scrolls the view to show it. The focused hyperlink is highlighted, and can
+// be opened with Gui.OpenHyperlinkKey. Returns false, and removes the focus,
+// if there is no next hyperlink.
+func (v *View) FocusNextHyperlink() bool {
+	ranges := v.hyperlinkRanges()
+	for _, r := range ranges {
+		if v.focusedHyperlink == nil && r.endY >= v.oy ||
+			v.focusedHyperlink != nil && (r.startY > v.focusedHyperlink.startY ||
+				r.startY == v.focusedHyperlink.startY && r.startX > v.focusedHyperlink.startX) {
+			v.focusHyperlink(r)
+			return true
+		}
+	}
+
+	v.focusedHyperlink = nil
+	return false
+}
+
+// FocusPreviousHyperlink moves the keyboard focus to the previous hyperlink
+// in the view, or to the last visible one if no hyperlink has the focus.
+// Returns false, and removes the focus, if there is no previous hyperlink.
+func (v *View) FocusPreviousHyperlink() bool {
+	ranges := v.hyperlinkRanges()
+	for i := len(ranges) - 1; i >= 0; i-- {
+		r := ranges[i]
+		if v.focusedHyperlink == nil && r.startY < v.oy+v.InnerHeight() ||
+			v.focusedHyperlink != nil && (r.startY < v.focusedHyperlink.startY ||
+				r.startY == v.focusedHyperlink.startY && r.startX < v.focusedHyperlink.startX) {
+			v.focusHyperlink(r)
+			return true
+		}
+	}
+
+	v.focusedHyperlink = nil
+	return false
+}
+
+// FocusedHyperlink returns the hyperlink that has the keyboard focus, or an
+// empty string if there is none.
+func (v *View) FocusedHyperlink() string {
+	if v.focusedHyperlink == nil {
+		return ""
+	}
+	return v.focusedHyperlink.link
+}
+
+// ClearHyperlinkFocus removes the keyboard focus from the focused hyperlink.
+func (v *View) ClearHyperlinkFocus() {
+	v.focusedHyperlink = nil
+}
+
+// focusHyperlink gives the keyboard focus to the given hyperlink, scrolling
+// the view so that it is visible.
+func (v *View) focusHyperlink(r hyperlinkRange) {
+	v.focusedHyperlink = &r
+
+	width, height := v.InnerSize()
+	if r.startY < v.oy {
+		v.oy = r.startY
+	} else if r.endY >= v.oy+height {
+		v.oy = min(r.startY, r.endY-height+1)
+	}
+	if !v.Wrap && r.startY == r.endY && (r.startX < v.ox || r.endX > v.ox+width) {
+		v.ox = max(0, min(r.startX, r.endX-width))
+	}
+}
+
+func (v *View) isFocusedHyperlink(contentX, y int) bool {
+	return v.focusedHyperlink != nil && v.focusedHyperlink.contains(contentX, y+v.oy)
+}
+
+// execHyperlinkKeybindings moves the keyboard focus between the hyperlinks
+// of the current view and opens them, if no keybinding handled the event.
+// Moving past the last hyperlink is not handled, so that the focus can move
+// on to the next view.
+func (g *Gui) execHyperlinkKeybindings(v *View, ev *GocuiEvent) (bool, error) {
+	if v == nil || v.Editable {
+		return false, nil
+	}
+
+	switch {
+	case g.NextHyperlinkKey != nil && eventMatchesKey(ev, g.NextHyperlinkKey):
+		return v.FocusNextHyperlink(), nil
+	case g.PrevHyperlinkKey != nil && eventMatchesKey(ev, g.PrevHyperlinkKey):
+		return v.FocusPreviousHyperlink(), nil
+	case g.OpenHyperlinkKey != nil && eventMatchesKey(ev, g.OpenHyperlinkKey):
+		if link := v.FocusedHyperlink(); link != "" {
+			return g.openLink(v, link)
+		}
+	}
+	return false, nil
+}
+
+// openLink opens a hyperlink of the given view, with the function of the link
+// detector that found it, if it has one. Returns false if there is no function
+// to open it with.
+func (g *Gui) openLink(v *View, link string) (bool, error) {
+	if open, ok := v.linkOpeners[link]; ok {
+		return true, open(link, v.name)
+	}
+	if g.openHyperlink != nil {
+		return true, g.openHyperlink(link, v.name)
+	}
+	return false, nil
+}
@@ -0,0 +1,115 @@
+package gocui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHyperlinkRanges(t *testing.T) {
+	v := NewView("v", 0, 0, 11, 10, OutputNormal)
+	v.Wrap = true
+	v.AutoRenderHyperLinks = true
+	// wrapped hyperlinks are a single range
+	v.SetContent("see https://a.com and\n\x1b]8;;https://b.com\x1b\\bbb\x1b]8;;\x1b\\\x1b]8;;https://c.com\x1b\\ccc\x1b]8;;\x1b\\\nhttps://wrapped.com")
+
+	assert.Equal(t, []hyperlinkRange{
+		{link: "https://a.com", startY: 1, startX: 0, endY: 2, endX: 3},
+		{link: "https://b.com", startY: 3, startX: 0, endY: 3, endX: 3},
+		{link: "https://c.com", startY: 3, startX: 3, endY: 3, endX: 6},
+		{link: "https://wrapped.com", startY: 4, startX: 0, endY: 5, endX: 9},
+	}, v.hyperlinkRanges())
+}
+
+func TestFocusHyperlinks(t *testing.T) {
+	v := NewView("v", 0, 0, 21, 4, OutputNormal)
+	v.AutoRenderHyperLinks = true
+	v.SetContent("https://a.com\nno link\nhttps://b.com https://c.com\nhttps://d.com")
+
+	assert.Equal(t, "", v.FocusedHyperlink())
+	assert.True(t, v.FocusNextHyperlink())
+	assert.Equal(t, "https://a.com", v.FocusedHyperlink())
+	assert.True(t, v.FocusNextHyperlink())
+	assert.Equal(t, "https://b.com", v.FocusedHyperlink())
+	assert.True(t, v.FocusNextHyperlink())
+	assert.Equal(t, "https://c.com", v.FocusedHyperlink())
+
+	// the view scrolls to show the focused hyperlink
+	assert.True(t, v.FocusNextHyperlink())
+	assert.Equal(t, "https://d.com", v.FocusedHyperlink())
+	assert.Equal(t, 1, v.OriginY())
+
+	assert.False(t, v.FocusNextHyperlink())
+	assert.Equal(t, "", v.FocusedHyperlink())
+
+	// without a focused hyperlink, we start at the last visible one
+	assert.True(t, v.FocusPreviousHyperlink())
+	assert.Equal(t, "https://d.com", v.FocusedHyperlink())
+	assert.True(t, v.FocusPreviousHyperlink())
+	assert.Equal(t, "https://c.com", v.FocusedHyperlink())
+
+	// new content removes the focus
+	v.SetContent("https://e.com")
+	assert.Equal(t, "", v.FocusedHyperlink())
+}
+
+func TestDrawFocusedHyperlink(t *testing.T) {
+	setupSimulationScreen(t, 20, 5)
+
+	v := NewView("v", 0, 0, 19, 4, OutputNormal)
+	v.AutoRenderHyperLinks = true
+	v.UnderlineHyperLinksOnlyOnHover = true
+	v.SetContent("go to https://a.com")
+	v.FocusNextHyperlink()
+	v.draw()
+
+	_, style, _ := Screen.Get(7, 1)
+	_, _, attrs := style.Decompose()
+	assert.NotZero(t, attrs&tcell.AttrReverse, "the focused hyperlink is reversed")
+	_, style, _ = Screen.Get(1, 1)
+	_, _, attrs = style.Decompose()
+	assert.Zero(t, attrs&tcell.AttrReverse)
+}
+
+func TestHyperlinkKeys(t *testing.T) {
+	g := &Gui{
+		NextFocusKey:     KeyTab,
+		NextHyperlinkKey: KeyTab,
+		PrevHyperlinkKey: KeyBacktab,
+		OpenHyperlinkKey: KeyEnter,
+	}
+	first := NewView("first", 0, 0, 20, 5, OutputNormal)
+	first.AutoRenderHyperLinks = true
+	first.SetContent("https://a.com https://b.com")
+	second := NewView("second", 0, 10, 20, 15, OutputNormal)
+	g.views = []*View{first, second}
+	g.SetFocusOrder("first", "second")
+	g.currentView = first
+
+	var opened []string
+	g.SetOpenHyperlinkFunc(func(link string, viewName string) error {
+		opened = append(opened, viewName+" "+link)
+		return nil
+	})
+
+	press := func(key Key) {
+		assert.NoError(t, g.execKeybindings(g.CurrentView(), &GocuiEvent{Type: eventKey, Key: key}))
+	}
+
+	// nothing is focused yet
+	press(KeyEnter)
+	assert.Empty(t, opened)
+
+	press(KeyTab)
+	press(KeyTab)
+	press(KeyBacktab)
+	press(KeyEnter)
+	assert.Equal(t, []string{"first https://a.com"}, opened)
+
+	// tabbing past the last hyperlink moves on to the next view
+	press(KeyTab)
+	press(KeyTab)
+	assert.Equal(t, second, g.CurrentView())
+	assert.Equal(t, "", first.FocusedHyperlink())
+}
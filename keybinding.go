@@ -6,6 +6,8 @@ package gocui
 
 import (
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/gdamore/tcell/v2"
 )
@@ -19,45 +21,164 @@ type Modifier tcell.ModMask
 
 // Keybidings are used to link a given key-press event with a handler.
 type keybinding struct {
-	viewName string
-	key      Key
-	ch       rune
-	mod      Modifier
-	handler  func(*Gui, *View) error
+	viewName    string
+	key         Key
+	ch          rune
+	mod         Modifier
+	handler     func(*Gui, *View) error
+	description string
+	category    string
 }
 
 // Parse takes the input string and extracts the keybinding.
 // Returns a Key / rune, a Modifier and an error.
+//
+// The input consists of any number of modifiers followed by a key, separated
+// by "+", e.g. "ctrl+shift+up", "alt+ctrl+k" or "Alt+Enter". Modifiers and key
+// names are case-insensitive; a single character (e.g. "a" or "A") is parsed
+// as a rune. Format does the reverse.
 func Parse(input string) (any, Modifier, error) {
-	if len(input) == 1 {
-		_, r, err := getKey(rune(input[0]))
-		if err != nil {
-			return nil, ModNone, err
-		}
+	if utf8.RuneCountInString(input) == 1 {
+		r, _ := utf8.DecodeRuneInString(input)
 		return r, ModNone, nil
 	}
 
-	var modifier Modifier
-	cleaned := make([]string, 0)
+	tokens := strings.Split(input, "+")
+	// a trailing "++" means that the key itself is '+'
+	if len(tokens) > 2 && tokens[len(tokens)-1] == "" && tokens[len(tokens)-2] == "" {
+		tokens = append(tokens[:len(tokens)-2], "+")
+	}
 
-	tokens := strings.SplitSeq(input, "+")
-	for t := range tokens {
-		normalized := strings.Title(strings.ToLower(t))
-		if t == "Alt" {
-			modifier = ModAlt
+	var ctrl, alt, shift bool
+	keyTokens := make([]string, 0, 1)
+	for _, t := range tokens {
+		var modifier *bool
+		switch strings.ToLower(t) {
+		case "ctrl", "control":
+			modifier = &ctrl
+		case "alt", "meta":
+			modifier = &alt
+		case "shift":
+			modifier = &shift
+		default:
+			keyTokens = append(keyTokens, t)
 			continue
 		}
-		cleaned = append(cleaned, normalized)
+		if *modifier {
+			return nil, ModNone, ErrNoSuchKeybind
+		}
+		*modifier = true
 	}
 
-	key, exist := translate[strings.Join(cleaned, "")]
-	if !exist {
+	// for backwards compatibility, a key name may itself be split into tokens,
+	// e.g. "Arrow+Up"
+	keyToken := strings.Join(keyTokens, "")
+	if keyToken == "" {
 		return nil, ModNone, ErrNoSuchKeybind
 	}
 
+	var modifier Modifier
+	if alt {
+		modifier |= ModAlt
+	}
+
+	if utf8.RuneCountInString(keyToken) == 1 {
+		r, _ := utf8.DecodeRuneInString(keyToken)
+		switch {
+		case ctrl:
+			key, ok := keyNamesToKeys["ctrl+"+strings.ToLower(keyToken)]
+			if !ok || shift {
+				return nil, ModNone, ErrNoSuchKeybind
+			}
+			return key, modifier, nil
+		case shift:
+			if !unicode.IsLetter(r) {
+				return nil, ModNone, ErrNoSuchKeybind
+			}
+			return unicode.ToUpper(r), modifier, nil
+		default:
+			return r, modifier, nil
+		}
+	}
+
+	name := strings.ToLower(keyToken)
+	// some keys have a modifier built in (e.g. KeyCtrlA or KeyBacktab)
+	if ctrl && !shift {
+		if key, ok := keyNamesToKeys["ctrl+"+name]; ok {
+			return key, modifier, nil
+		}
+	}
+	if shift && !ctrl && (!alt || name == "tab") {
+		if key, ok := keyNamesToKeys["shift+"+name]; ok {
+			return key, modifier, nil
+		}
+	}
+	if alt && !ctrl && !shift {
+		if key, ok := keyNamesToKeys["alt+"+name]; ok {
+			return key, ModNone, nil
+		}
+	}
+
+	key, ok := keyNamesToKeys[name]
+	if !ok {
+		return nil, ModNone, ErrNoSuchKeybind
+	}
+	if ctrl {
+		modifier |= ModCtrl
+	}
+	if shift {
+		modifier |= ModShift
+	}
+
 	return key, modifier, nil
 }
 
+// Format turns a Key / rune and a Modifier into a canonical string that can
+// be parsed back with Parse, e.g. "ctrl+alt+k" or "shift+up".
+func Format(key any, mod Modifier) string {
+	k, ch, err := getKey(key)
+	if err != nil {
+		return ""
+	}
+
+	name := string(ch)
+	if ch == 0 {
+		var ok bool
+		name, ok = keyNames[k]
+		if !ok {
+			return ""
+		}
+	}
+
+	// merge the modifier that is built into the key (if any) with the other
+	// modifiers, so that we always output them in the same order
+	ctrl := mod&ModCtrl != 0
+	alt := mod&ModAlt != 0
+	shift := mod&ModShift != 0
+	if ch == 0 {
+		if rest, found := strings.CutPrefix(name, "ctrl+"); found {
+			name, ctrl = rest, true
+		} else if rest, found := strings.CutPrefix(name, "alt+"); found {
+			name, alt = rest, true
+		} else if rest, found := strings.CutPrefix(name, "shift+"); found {
+			name, shift = rest, true
+		}
+	}
+
+	var b strings.Builder
+	if ctrl {
+		b.WriteString("ctrl+")
+	}
+	if alt {
+		b.WriteString("alt+")
+	}
+	if shift {
+		b.WriteString("shift+")
+	}
+	b.WriteString(name)
+	return b.String()
+}
+
 // ParseAll takes an array of strings and returns a map of all keybindings.
 func ParseAll(input []string) (map[any]Modifier, error) {
 	ret := make(map[any]Modifier)
@@ -117,9 +238,18 @@ func eventMatchesKey(ev *GocuiEvent, key any) bool {
 	return k == ev.Key && ch == ev.Ch
 }
 
-// matchKeypress returns if the keybinding matches the keypress.
-func (kb *keybinding) matchKeypress(key Key, ch rune, mod Modifier) bool {
-	return kb.key == key && kb.ch == ch && kb.mod == mod
+// matchKeypress returns if the keybinding matches the event.
+func (kb *keybinding) matchKeypress(ev *GocuiEvent) bool {
+	if kb.key == ev.Key && kb.ch == ev.Ch && kb.mod == ev.Mod {
+		return true
+	}
+
+	// the legacy keys share their values with mouse keys, so only keyboard
+	// events may match them as the key combination they stand for
+	if legacyKey, ok := legacyKeys[kb.key]; ok && ev.Type == eventKey && kb.ch == 0 && ev.Ch == 0 {
+		return legacyKey.key == ev.Key && kb.mod|legacyKey.mod == ev.Mod
+	}
+	return false
 }
 
 // translations for strings to keys
@@ -203,6 +333,130 @@ var translate = map[string]Key{
 	"MousewheelDown": MouseWheelDown,
 }
 
+// keyNames maps keys to the canonical names used by Format. Note that some of
+// the mouse keys share their values with keyboard keys (see below); we use the
+// names of the keyboard keys for those.
+var keyNames = map[Key]string{
+	KeyF1:             "f1",
+	KeyF2:             "f2",
+	KeyF3:             "f3",
+	KeyF4:             "f4",
+	KeyF5:             "f5",
+	KeyF6:             "f6",
+	KeyF7:             "f7",
+	KeyF8:             "f8",
+	KeyF9:             "f9",
+	KeyF10:            "f10",
+	KeyF11:            "f11",
+	KeyF12:            "f12",
+	KeyInsert:         "insert",
+	KeyDelete:         "delete",
+	KeyHome:           "home",
+	KeyEnd:            "end",
+	KeyPgup:           "pgup",
+	KeyPgdn:           "pgdn",
+	KeyArrowUp:        "up",
+	KeyArrowDown:      "down",
+	KeyArrowLeft:      "left",
+	KeyArrowRight:     "right",
+	KeyTab:            "tab",
+	KeyBacktab:        "shift+tab",
+	KeyEnter:          "enter",
+	KeyEsc:            "esc",
+	KeySpace:          "space",
+	KeyBackspace:      "backspace",
+	KeyBackspace2:     "backspace2",
+	KeyCtrlSpace:      "ctrl+space",
+	KeyCtrlA:          "ctrl+a",
+	KeyCtrlB:          "ctrl+b",
+	KeyCtrlC:          "ctrl+c",
+	KeyCtrlD:          "ctrl+d",
+	KeyCtrlE:          "ctrl+e",
+	KeyCtrlF:          "ctrl+f",
+	KeyCtrlG:          "ctrl+g",
+	KeyCtrlH:          "ctrl+h",
+	KeyCtrlI:          "ctrl+i",
+	KeyCtrlJ:          "ctrl+j",
+	KeyCtrlK:          "ctrl+k",
+	KeyCtrlL:          "ctrl+l",
+	KeyCtrlM:          "ctrl+m",
+	KeyCtrlN:          "ctrl+n",
+	KeyCtrlO:          "ctrl+o",
+	KeyCtrlP:          "ctrl+p",
+	KeyCtrlQ:          "ctrl+q",
+	KeyCtrlR:          "ctrl+r",
+	KeyCtrlS:          "ctrl+s",
+	KeyCtrlT:          "ctrl+t",
+	KeyCtrlU:          "ctrl+u",
+	KeyCtrlV:          "ctrl+v",
+	KeyCtrlW:          "ctrl+w",
+	KeyCtrlX:          "ctrl+x",
+	KeyCtrlY:          "ctrl+y",
+	KeyCtrlZ:          "ctrl+z",
+	KeyCtrlLsqBracket: "ctrl+[",
+	KeyCtrlBackslash:  "ctrl+\\",
+	KeyCtrlRsqBracket: "ctrl+]",
+	KeyCtrl6:          "ctrl+^",
+	KeyCtrlUnderscore: "ctrl+_",
+	KeyCtrlTilde:      "ctrl+~",
+	MouseLeft:         "mouseleft",
+	MouseRight:        "mouseright",
+	MouseMiddle:       "mousemiddle",
+	MouseRelease:      "mouserelease",
+	MouseWheelUp:      "mousewheelup",
+	MouseWheelDown:    "mousewheeldown",
+	MouseWheelLeft:    "mousewheelleft",
+	MouseWheelRight:   "mousewheelright",
+}
+
+// legacyKeys are placeholder keys that we used to report for some key
+// combinations before we passed on the modifiers of special keys. Keybindings
+// for them still work: they match the key combination they stand for. Their
+// values are shared with MouseLeft, MouseRight and KeyCtrlTilde, which is what
+// Format names them.
+var legacyKeys = map[Key]struct {
+	key Key
+	mod Modifier
+}{
+	KeyShiftArrowUp:   {KeyArrowUp, ModShift},
+	KeyShiftArrowDown: {KeyArrowDown, ModShift},
+	KeyAltEnter:       {KeyEnter, ModAlt},
+}
+
+// keyNamesToKeys maps lower-case key names to keys for Parse. Besides the
+// canonical names, it also accepts the names from the translate table and a
+// few common aliases.
+var keyNamesToKeys = func() map[string]Key {
+	result := map[string]Key{
+		"arrowup":    KeyArrowUp,
+		"arrowdown":  KeyArrowDown,
+		"arrowleft":  KeyArrowLeft,
+		"arrowright": KeyArrowRight,
+		"pageup":     KeyPgup,
+		"pagedown":   KeyPgdn,
+		"escape":     KeyEsc,
+		"return":     KeyEnter,
+		"del":        KeyDelete,
+		"ins":        KeyInsert,
+		"ctrl+~":     KeyCtrlTilde,
+		"ctrl+2":     KeyCtrl2,
+		"ctrl+3":     KeyCtrl3,
+		"ctrl+4":     KeyCtrl4,
+		"ctrl+5":     KeyCtrl5,
+		"ctrl+6":     KeyCtrl6,
+		"ctrl+7":     KeyCtrl7,
+		"ctrl+8":     KeyCtrl8,
+		"ctrl+/":     KeyCtrlSlash,
+	}
+	for name, key := range translate {
+		result[strings.ToLower(name)] = key
+	}
+	for key, name := range keyNames {
+		result[name] = key
+	}
+	return result
+}()
+
 // Special keys.
 const (
 	KeyF1             Key = Key(tcell.KeyF1)
@@ -224,9 +478,9 @@ const (
 	KeyPgdn               = Key(tcell.KeyPgDn)
 	KeyPgup               = Key(tcell.KeyPgUp)
 	KeyArrowUp            = Key(tcell.KeyUp)
-	KeyShiftArrowUp       = Key(tcell.KeyF62)
+	KeyShiftArrowUp       = Key(tcell.KeyF62) // legacy, same as KeyArrowUp with ModShift
 	KeyArrowDown          = Key(tcell.KeyDown)
-	KeyShiftArrowDown     = Key(tcell.KeyF63)
+	KeyShiftArrowDown     = Key(tcell.KeyF63) // legacy, same as KeyArrowDown with ModShift
 	KeyArrowLeft          = Key(tcell.KeyLeft)
 	KeyArrowRight         = Key(tcell.KeyRight)
 )
@@ -275,7 +529,7 @@ const (
 	// In tcell, these are not keys per se. But in gocui we have them
 	// mapped to the keys so we have to use placeholder keys.
 
-	KeyAltEnter       = Key(tcell.KeyF64) // arbitrary assignments
+	KeyAltEnter       = Key(tcell.KeyF64) // legacy, same as KeyEnter with ModAlt
 	MouseLeft         = Key(tcell.KeyF63)
 	MouseRight        = Key(tcell.KeyF62)
 	MouseMiddle       = Key(tcell.KeyF61)
@@ -300,7 +554,10 @@ const (
 const (
 	ModNone   Modifier = Modifier(0)
 	ModAlt             = Modifier(tcell.ModAlt)
-	ModMotion          = Modifier(2) // just picking an arbitrary number here that doesn't clash with tcell.ModAlt
-	// ModCtrl doesn't work with keyboard keys. Use CtrlKey in Key and ModNone. This is was for mouse clicks only (tcell.v1)
-	// ModCtrl = Modifier(tcell.ModCtrl)
+	ModMotion          = Modifier(1 << 8) // just picking an arbitrary bit here that doesn't clash with tcell's modifiers
+	// ModShift and ModCtrl are reported for special keys (e.g. ctrl+up or shift+home) and for mouse
+	// clicks. For letters, use the KeyCtrl* keys with ModNone instead of ModCtrl, and the upper-case
+	// rune instead of ModShift.
+	ModShift = Modifier(tcell.ModShift)
+	ModCtrl  = Modifier(tcell.ModCtrl)
 )
@@ -0,0 +1,192 @@
+package gocui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input       string
+		expectedKey any
+		expectedMod Modifier
+		expectedErr error
+	}{
+		{"a", 'a', ModNone, nil},
+		{"A", 'A', ModNone, nil},
+		{"ü", 'ü', ModNone, nil},
+		{"alt+a", 'a', ModAlt, nil},
+		{"Alt+a", 'a', ModAlt, nil},
+		{"ALT+a", 'a', ModAlt, nil},
+		{"shift+a", 'A', ModNone, nil},
+		{"alt++", '+', ModAlt, nil},
+		{"ctrl+a", KeyCtrlA, ModNone, nil},
+		{"Ctrl+A", KeyCtrlA, ModNone, nil},
+		{"alt+ctrl+k", KeyCtrlK, ModAlt, nil},
+		{"ctrl+alt+k", KeyCtrlK, ModAlt, nil},
+		{"ctrl+space", KeyCtrlSpace, ModNone, nil},
+		{"ctrl+\\", KeyCtrlBackslash, ModNone, nil},
+		{"ctrl+/", KeyCtrlSlash, ModNone, nil},
+		{"enter", KeyEnter, ModNone, nil},
+		{"Return", KeyEnter, ModNone, nil},
+		{"alt+enter", KeyEnter, ModAlt, nil},
+		{"up", KeyArrowUp, ModNone, nil},
+		{"shift+up", KeyArrowUp, ModShift, nil},
+		{"shift+home", KeyHome, ModShift, nil},
+		{"ctrl+enter", KeyEnter, ModCtrl, nil},
+		{"ctrl+up", KeyArrowUp, ModCtrl, nil},
+		{"ctrl+shift+up", KeyArrowUp, ModCtrl | ModShift, nil},
+		{"alt+shift+down", KeyArrowDown, ModAlt | ModShift, nil},
+		{"shift+tab", KeyBacktab, ModNone, nil},
+		{"pageup", KeyPgup, ModNone, nil},
+		{"F5", KeyF5, ModNone, nil},
+		{"mousewheelup", MouseWheelUp, ModNone, nil},
+		// legacy formats
+		{"Alt+CtrlA", KeyCtrlA, ModAlt, nil},
+		{"Arrow+Up", KeyArrowUp, ModNone, nil},
+		{"ArrowUp", KeyArrowUp, ModNone, nil},
+		{"Pgdn", KeyPgdn, ModNone, nil},
+		{"ShiftArrowUp", KeyShiftArrowUp, ModNone, nil},
+		// errors
+		{"", nil, ModNone, ErrNoSuchKeybind},
+		{"ctrl", nil, ModNone, ErrNoSuchKeybind},
+		{"ctrl+ctrl+a", nil, ModNone, ErrNoSuchKeybind},
+		{"ctrl+%", nil, ModNone, ErrNoSuchKeybind},
+		{"shift+1", nil, ModNone, ErrNoSuchKeybind},
+		{"hyper+a", nil, ModNone, ErrNoSuchKeybind},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			key, mod, err := Parse(test.input)
+			assert.Equal(t, test.expectedErr, err)
+			assert.Equal(t, test.expectedKey, key)
+			assert.Equal(t, test.expectedMod, mod)
+		})
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	canonical := []string{
+		"a",
+		"A",
+		"+",
+		"alt+a",
+		"alt++",
+		"ctrl+a",
+		"ctrl+alt+k",
+		"ctrl+space",
+		"ctrl+\\",
+		"ctrl+]",
+		"enter",
+		"alt+enter",
+		"esc",
+		"space",
+		"tab",
+		"shift+tab",
+		"alt+shift+tab",
+		"up",
+		"shift+up",
+		"ctrl+up",
+		"ctrl+shift+up",
+		"ctrl+alt+shift+left",
+		"alt+home",
+		"pgdn",
+		"f12",
+		"mousewheeldown",
+		"mouseleft",
+		"mouseright",
+		"ctrl+~",
+	}
+
+	for _, str := range canonical {
+		t.Run(str, func(t *testing.T) {
+			key, mod, err := Parse(str)
+			assert.NoError(t, err)
+			assert.Equal(t, str, Format(key, mod))
+		})
+	}
+
+	for key := range keyNames {
+		for _, mod := range []Modifier{ModNone, ModAlt} {
+			str := Format(key, mod)
+			parsedKey, parsedMod, err := Parse(str)
+			assert.NoError(t, err, str)
+			assert.Equal(t, key, parsedKey, str)
+			assert.Equal(t, mod, parsedMod, str)
+		}
+	}
+
+}
+
+func TestMatchKeypress(t *testing.T) {
+	tests := []struct {
+		name     string
+		kbKey    Key
+		kbMod    Modifier
+		key      Key
+		mod      Modifier
+		expected bool
+	}{
+		{"exact match", KeyArrowUp, ModShift, KeyArrowUp, ModShift, true},
+		{"modifier mismatch", KeyArrowUp, ModNone, KeyArrowUp, ModShift, false},
+		{"extra modifier", KeyHome, ModCtrl, KeyHome, ModCtrl | ModShift, false},
+		{"legacy shift+up", KeyShiftArrowUp, ModNone, KeyArrowUp, ModShift, true},
+		{"legacy shift+down", KeyShiftArrowDown, ModNone, KeyArrowDown, ModShift, true},
+		{"legacy alt+enter", KeyAltEnter, ModNone, KeyEnter, ModAlt, true},
+		{"legacy key with extra modifier", KeyShiftArrowUp, ModAlt, KeyArrowUp, ModAlt | ModShift, true},
+		{"legacy key without modifier", KeyShiftArrowUp, ModNone, KeyArrowUp, ModNone, false},
+		{"mouse key", MouseLeft, ModNone, MouseLeft, ModNone, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kb := newKeybinding("", test.kbKey, 0, test.kbMod, nil)
+			eventType := eventKey
+			if IsMouseKey(test.key) {
+				eventType = eventMouse
+			}
+			ev := &GocuiEvent{Type: eventType, Key: test.key, Mod: test.mod}
+			assert.Equal(t, test.expected, kb.matchKeypress(ev))
+		})
+	}
+}
+
+func TestPollEventMatchesParsedKeybinding(t *testing.T) {
+	tests := []struct {
+		keybinding string
+		key        tcell.Key
+		ch         rune
+		mod        tcell.ModMask
+	}{
+		{"ctrl+enter", tcell.KeyEnter, 0, tcell.ModCtrl},
+		{"ctrl+tab", tcell.KeyTab, 0, tcell.ModCtrl},
+		{"ctrl+a", tcell.KeyCtrlA, 0, tcell.ModCtrl},
+		{"ctrl+space", tcell.KeyRune, ' ', tcell.ModCtrl},
+		{"enter", tcell.KeyEnter, 0, tcell.ModNone},
+		{"A", tcell.KeyRune, 'A', tcell.ModShift},
+	}
+
+	for _, test := range tests {
+		t.Run(test.keybinding, func(t *testing.T) {
+			setupSimulationScreen(t, 10, 10)
+			g := &Gui{}
+
+			key, mod, err := Parse(test.keybinding)
+			assert.NoError(t, err)
+			var kb *keybinding
+			switch key := key.(type) {
+			case Key:
+				kb = newKeybinding("", key, 0, mod, nil)
+			case rune:
+				kb = newKeybinding("", 0, key, mod, nil)
+			}
+
+			Screen.(tcell.SimulationScreen).InjectKey(test.key, test.ch, test.mod)
+			ev := g.pollEvent()
+			assert.True(t, kb.matchKeypress(&ev))
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package gocui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+var urlRe = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://\S+`)
+
+// lineBreakOpportunities returns for each character (grapheme cluster) of a
+// line whether the line may be broken after it, according to the Unicode line
+// breaking algorithm (UAX #14), and whether it is part of a URL. There are no
+// break opportunities inside URLs, so that they keep working when the text is
+// copied or used as e.g. a commit message. This is used both for wrapping
+// views and for auto-wrapping text areas.
+func lineBreakOpportunities(chrs []string) (breakAfter []bool, inURL []bool) {
+	ends := make([]int, len(chrs))
+	var b strings.Builder
+	for i, chr := range chrs {
+		b.WriteString(chr)
+		ends[i] = b.Len()
+	}
+	line := b.String()
+
+	breakAfter = make([]bool, len(chrs))
+	rest, offset, state, i := line, 0, -1, 0
+	for rest != "" {
+		var segment string
+		segment, rest, _, state = uniseg.FirstLineSegmentInString(rest, state)
+		offset += len(segment)
+		for i < len(chrs)-1 && ends[i] < offset {
+			i++
+		}
+		if rest != "" {
+			breakAfter[i] = true
+		}
+	}
+
+	inURL = make([]bool, len(chrs))
+	if !strings.Contains(line, "://") {
+		return breakAfter, inURL
+	}
+	urls := urlRe.FindAllStringIndex(line, -1)
+	for i, end := range ends {
+		for len(urls) > 0 && urls[0][1] < end {
+			urls = urls[1:]
+		}
+		if len(urls) > 0 && end > urls[0][0] {
+			inURL[i] = true
+			// a URL may still be followed by a break
+			breakAfter[i] = breakAfter[i] && end == urls[0][1]
+		}
+	}
+	return breakAfter, inURL
+}
@@ -0,0 +1,168 @@
+package gocui
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// LinkDetector turns text written to a view into hyperlinks, e.g. file
+// locations or issue references. Detectors are run on each line when it is
+// written, after the https:// URLs of AutoRenderHyperLinks; text that is
+// already part of a hyperlink is left alone.
+type LinkDetector struct {
+	// Pattern matches the text of the links.
+	Pattern *regexp.Regexp
+
+	// URL is the template for the hyperlink of a match of Pattern, in the
+	// syntax of regexp.Expand, e.g. "https://github.com/org/repo/issues/$1".
+	// If empty, the matched text itself is the hyperlink.
+	URL string
+
+	// Detect, if set, is used instead of Pattern to find the links in a line.
+	Detect func(line string) []LinkMatch
+
+	// Open, if set, is called instead of the function set by
+	// Gui.SetOpenHyperlinkFunc to open the links found by this detector.
+	Open func(link string, viewName string) error
+}
+
+// LinkMatch is a link found by a LinkDetector's Detect function. Start and End
+// are byte offsets into the line.
+type LinkMatch struct {
+	Start, End int
+	URL        string
+}
+
+// NewURLLinkDetector returns a detector for http:// and https:// URLs. Like
+// the URLs of AutoRenderHyperLinks, they end at whitespace or one of the
+// lineEndCharacters; in addition, trailing punctuation such as the full stop
+// of a sentence is left out.
+func NewURLLinkDetector() *LinkDetector {
+	return &LinkDetector{Pattern: urlPattern()}
+}
+
+func urlPattern() *regexp.Regexp {
+	endCharacters := []string{`\s`}
+	for chr := range lineEndCharacters {
+		endCharacters = append(endCharacters, regexp.QuoteMeta(chr))
+	}
+	slices.Sort(endCharacters)
+	end := strings.Join(endCharacters, "")
+	return regexp.MustCompile(fmt.Sprintf(`https?://[^%[1]s]*[^%[1]s.,:;!?]`, end))
+}
+
+// NewIssueLinkDetector returns a detector for issue references like #1234,
+// where $1 in the URL template is the issue number.
+func NewIssueLinkDetector(url string) *LinkDetector {
+	return &LinkDetector{Pattern: regexp.MustCompile(`\B#(\d+)\b`), URL: url}
+}
+
+// NewCommitLinkDetector returns a detector for abbreviated or full commit
+// hashes, where $0 in the URL template is the hash. Hashes must contain at
+// least one of the letters a-f, so that plain numbers like 20240101 aren't
+// taken for hashes; the rare hashes consisting of digits only are missed.
+func NewCommitLinkDetector(url string) *LinkDetector {
+	hashes := &LinkDetector{Pattern: regexp.MustCompile(`\b[0-9a-f]{7,40}\b`), URL: url}
+	return &LinkDetector{
+		Detect: func(line string) []LinkMatch {
+			return slices.DeleteFunc(hashes.find(line), func(match LinkMatch) bool {
+				return !strings.ContainsAny(line[match.Start:match.End], "abcdef")
+			})
+		},
+	}
+}
+
+// NewFileLocationLinkDetector returns a detector for file locations like
+// path/to/file.go:123, where $1 in the URL template is the path and $2 the
+// line number. Paths must contain a slash or a dot.
+func NewFileLocationLinkDetector(url string) *LinkDetector {
+	return &LinkDetector{Pattern: regexp.MustCompile(`\b([\w.-]*[/.][\w./-]*\w):(\d+)\b`), URL: url}
+}
+
+func (d *LinkDetector) find(line string) []LinkMatch {
+	if d.Detect != nil {
+		return d.Detect(line)
+	}
+	if d.Pattern == nil {
+		return nil
+	}
+
+	var matches []LinkMatch
+	for _, submatches := range d.Pattern.FindAllStringSubmatchIndex(line, -1) {
+		url := line[submatches[0]:submatches[1]]
+		if d.URL != "" {
+			url = string(d.Pattern.ExpandString(nil, d.URL, line, submatches))
+		}
+		matches = append(matches, LinkMatch{Start: submatches[0], End: submatches[1], URL: url})
+	}
+	return matches
+}
+
+// detectedLink is a hyperlink that a link detector found in the line that is
+// currently written to.
+type detectedLink struct {
+	xStart, xEnd int
+	url          string
+}
+
+// detectLinksInCurrentLine runs the view's link detectors on the line that is
+// currently written to, replacing the links they found in it before.
+func (v *View) detectLinksInCurrentLine() {
+	line := v.lines[v.wy]
+	for _, link := range v.detectedLinks[v.wy] {
+		for x := link.xStart; x < link.xEnd && x < len(line); x++ {
+			if line[x].hyperlink == link.url {
+				line[x].hyperlink = ""
+			}
+		}
+	}
+	delete(v.detectedLinks, v.wy)
+
+	// cellIdx maps byte offsets of the line's text to the indices of the cells
+	var text strings.Builder
+	cellIdx := make([]int, 0, len(line)+1)
+	for i, c := range line {
+		text.WriteString(c.chr)
+		for range len(c.chr) {
+			cellIdx = append(cellIdx, i)
+		}
+	}
+	cellIdx = append(cellIdx, len(line))
+	str := text.String()
+
+	for _, detector := range v.LinkDetectors {
+		for _, match := range detector.find(str) {
+			if match.Start < 0 || match.End > len(str) || match.Start >= match.End {
+				continue
+			}
+			xStart, xEnd := cellIdx[match.Start], cellIdx[match.End]
+			if hasHyperlink(line[xStart:xEnd]) {
+				continue
+			}
+			for x := xStart; x < xEnd; x++ {
+				line[x].hyperlink = match.URL
+			}
+			if v.detectedLinks == nil {
+				v.detectedLinks = map[int][]detectedLink{}
+			}
+			v.detectedLinks[v.wy] = append(v.detectedLinks[v.wy], detectedLink{xStart: xStart, xEnd: xEnd, url: match.URL})
+			if detector.Open != nil {
+				if v.linkOpeners == nil {
+					v.linkOpeners = map[string]func(string, string) error{}
+				}
+				v.linkOpeners[match.URL] = detector.Open
+			}
+		}
+	}
+}
+
+func hasHyperlink(cells []cell) bool {
+	for _, c := range cells {
+		if c.hyperlink != "" {
+			return true
+		}
+	}
+	return false
+}
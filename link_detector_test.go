@@ -0,0 +1,155 @@
+package gocui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// lineHyperlinks returns the hyperlinks in a line of the view, with the text
+// that they cover.
+func lineHyperlinks(v *View, y int) []string {
+	var links []string
+	var text strings.Builder
+	link := ""
+	flush := func() {
+		if link != "" {
+			links = append(links, text.String()+" -> "+link)
+		}
+		text.Reset()
+	}
+	for _, c := range v.lines[y] {
+		if c.hyperlink != link {
+			flush()
+			link = c.hyperlink
+		}
+		text.WriteString(c.chr)
+	}
+	flush()
+	return links
+}
+
+func TestLinkDetectors(t *testing.T) {
+	v := NewView("v", 0, 0, 50, 10, OutputNormal)
+	v.AutoRenderHyperLinks = true
+	v.LinkDetectors = []*LinkDetector{
+		NewIssueLinkDetector("https://example.com/issues/$1"),
+		NewCommitLinkDetector("https://example.com/commit/$0"),
+		NewFileLocationLinkDetector("file://$1#L$2"),
+		NewURLLinkDetector(),
+	}
+	v.SetContent(strings.Join([]string{
+		"fixes #1234 in a1b2c3d",
+		"see view.go:12 and pkg/gui/app.go:3",
+		"http://a.com and https://b.com/x.go:1",
+		"not links: abc#12 deadbeefz 12:30",
+	}, "\n"))
+
+	assert.Equal(t, []string{
+		"#1234 -> https://example.com/issues/1234",
+		"a1b2c3d -> https://example.com/commit/a1b2c3d",
+	}, lineHyperlinks(v, 0))
+	assert.Equal(t, []string{
+		"view.go:12 -> file://view.go#L12",
+		"pkg/gui/app.go:3 -> file://pkg/gui/app.go#L3",
+	}, lineHyperlinks(v, 1))
+	// the https:// URL was found by AutoRenderHyperLinks, and isn't taken
+	// apart by the other detectors
+	assert.Equal(t, []string{
+		"http://a.com -> http://a.com",
+		"https://b.com/x.go:1 -> https://b.com/x.go:1",
+	}, lineHyperlinks(v, 2))
+	assert.Empty(t, lineHyperlinks(v, 3))
+}
+
+func TestURLAndCommitLinkDetectors(t *testing.T) {
+	v := NewView("v", 0, 0, 80, 10, OutputNormal)
+	v.LinkDetectors = []*LinkDetector{
+		NewCommitLinkDetector("https://example.com/commit/$0"),
+		NewURLLinkDetector(),
+	}
+	v.SetContent(strings.Join([]string{
+		"see https://a.com/x, https://b.com/y. and (https://c.com/z):",
+		"released on 20240101 in 1234567abc and 0123456",
+		"https://d.com/a.b?c=d#e!",
+	}, "\n"))
+
+	assert.Equal(t, []string{
+		"https://a.com/x -> https://a.com/x",
+		"https://b.com/y -> https://b.com/y",
+		"https://c.com/z -> https://c.com/z",
+	}, lineHyperlinks(v, 0))
+	assert.Equal(t, []string{
+		"1234567abc -> https://example.com/commit/1234567abc",
+	}, lineHyperlinks(v, 1))
+	assert.Equal(t, []string{
+		"https://d.com/a.b?c=d#e -> https://d.com/a.b?c=d#e",
+	}, lineHyperlinks(v, 2))
+}
+
+func TestLinkDetectorsWithIncompleteLines(t *testing.T) {
+	v := NewView("v", 0, 0, 50, 10, OutputNormal)
+	calls := 0
+	v.LinkDetectors = []*LinkDetector{
+		{
+			Detect: func(line string) []LinkMatch {
+				calls++
+				return NewIssueLinkDetector("#$1").find(line)
+			},
+		},
+	}
+
+	v.writeString("one #1\ntwo #2\n")
+	assert.Equal(t, 2, calls)
+
+	// writing more to a line updates its links
+	v.writeString("three #3")
+	assert.Equal(t, []string{"#3 -> #3"}, lineHyperlinks(v, 2))
+	v.writeString("4")
+	assert.Equal(t, []string{"#34 -> #34"}, lineHyperlinks(v, 2))
+	assert.Equal(t, 4, calls)
+
+	// unchanged lines are not scanned again
+	v.writeString("")
+	assert.Equal(t, 4, calls)
+	assert.Equal(t, []string{"#1 -> #1"}, lineHyperlinks(v, 0))
+
+	// rewriting a line only scans that line, and the links found in other
+	// lines are remembered
+	v.SetWritePos(4, 1)
+	v.writeString("#5")
+	assert.Equal(t, 5, calls)
+	assert.Equal(t, []string{"#5 -> #5"}, lineHyperlinks(v, 1))
+	assert.Equal(t, []string{"#1 -> #1"}, lineHyperlinks(v, 0))
+
+	v.SetWritePos(6, 2)
+	v.writeString("x")
+	assert.Equal(t, 6, calls)
+	assert.Equal(t, "three x34", lineType(v.lines[2]).String())
+	assert.Empty(t, lineHyperlinks(v, 2))
+}
+
+func TestLinkDetectorOpen(t *testing.T) {
+	g := &Gui{maxX: 40, maxY: 20}
+	v, _ := g.SetView("v", 0, 0, 30, 10, 0)
+
+	var opened []string
+	g.SetOpenHyperlinkFunc(func(link string, viewName string) error {
+		opened = append(opened, "gui "+link)
+		return nil
+	})
+	issues := NewIssueLinkDetector("https://example.com/issues/$1")
+	files := NewFileLocationLinkDetector("$1:$2")
+	files.Open = func(link string, viewName string) error {
+		opened = append(opened, viewName+" "+link)
+		return nil
+	}
+	v.LinkDetectors = []*LinkDetector{issues, files}
+	v.SetContent("#12 main.go:3")
+	v.refreshViewLinesIfNeeded()
+
+	assert.NoError(t, g.onKey(&GocuiEvent{Type: eventMouse, Key: MouseLeft, MouseX: 2, MouseY: 1}))
+	assert.NoError(t, g.onKey(&GocuiEvent{Type: eventMouse, Key: MouseLeft, MouseX: 6, MouseY: 1}))
+	assert.Equal(t, []string{"gui https://example.com/issues/12", "v main.go:3"}, opened)
+}
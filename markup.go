@@ -0,0 +1,189 @@
+package gocui
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// maxMarkupTagLength is the length after which we give up on finding the end
+// of a tag, and show the text literally instead.
+const maxMarkupTagLength = 256
+
+// markupColors maps the names of the basic colors to gocui's colors, so that
+// e.g. "red" is the same color as ColorRed. Other names are looked up in
+// tcell's list of W3C color names.
+var markupColors = map[string]Attribute{
+	"default": ColorDefault,
+	"black":   ColorBlack,
+	"red":     ColorRed,
+	"green":   ColorGreen,
+	"yellow":  ColorYellow,
+	"blue":    ColorBlue,
+	"magenta": ColorMagenta,
+	"cyan":    ColorCyan,
+	"white":   ColorWhite,
+}
+
+var markupAttributes = map[rune]Attribute{
+	'b': AttrBold,
+	'd': AttrDim,
+	'i': AttrItalic,
+	'u': AttrUnderline,
+	'l': AttrBlink,
+	'r': AttrReverse,
+	's': AttrStrikeThrough,
+}
+
+// markupParser parses the style tags in text written to a view with markup.
+// See WriteMarkup for the syntax.
+type markupParser struct {
+	inTag bool
+	tag   strings.Builder
+}
+
+// parseOne parses a character (grapheme cluster), applying the style of
+// completed tags to the escape interpreter. It returns any text that turned
+// out not to be markup and must be shown literally, and whether the character
+// was consumed.
+func (mp *markupParser) parseOne(ch []byte, ei *escapeInterpreter) (string, bool) {
+	if !mp.inTag {
+		if characterEquals(ch, '[') {
+			mp.inTag = true
+			mp.tag.Reset()
+			return "", true
+		}
+		return "", false
+	}
+
+	switch {
+	case characterEquals(ch, '[') && mp.tag.Len() == 0:
+		mp.inTag = false
+		return "[", true
+	case characterEquals(ch, ']'):
+		mp.inTag = false
+		if !applyMarkupTag(mp.tag.String(), ei) {
+			return "[" + mp.tag.String() + "]", true
+		}
+		return "", true
+	case characterEquals(ch, '\n') || characterEquals(ch, '\r') || isCRLF(ch):
+		return mp.flush(), false
+	case mp.tag.Len()+len(ch) > maxMarkupTagLength:
+		return mp.flush() + string(ch), true
+	default:
+		mp.tag.Write(ch)
+		return "", true
+	}
+}
+
+// flush returns the text of an unfinished tag, to be shown literally.
+func (mp *markupParser) flush() string {
+	if !mp.inTag {
+		return ""
+	}
+	mp.inTag = false
+	return "[" + mp.tag.String()
+}
+
+// applyMarkupTag applies the style of a tag, without its brackets, to the
+// escape interpreter. Returns false, leaving the style unchanged, if the tag
+// is not valid.
+func applyMarkupTag(tag string, ei *escapeInterpreter) bool {
+	if tag == "-" {
+		tag = "-:-:-:-"
+	}
+	if strings.Trim(tag, ":") == "" {
+		return false
+	}
+	fields := strings.SplitN(tag, ":", 4)
+
+	bgColor := ei.curBgColor
+	fgColor, ok := applyMarkupColor(fields[0], ei.curFgColor)
+	if ok && len(fields) > 1 {
+		bgColor, ok = applyMarkupColor(fields[1], bgColor)
+	}
+	if ok && len(fields) > 2 {
+		switch fields[2] {
+		case "":
+		case "-":
+			fgColor &= AttrColorBits
+		default:
+			for _, r := range fields[2] {
+				attr, found := markupAttributes[r]
+				if !found {
+					return false
+				}
+				fgColor |= attr
+			}
+		}
+	}
+	if !ok {
+		return false
+	}
+
+	ei.curFgColor, ei.curBgColor = fgColor, bgColor
+	if len(fields) > 3 {
+		switch fields[3] {
+		case "":
+		case "-":
+			ei.hyperlink.Reset()
+		default:
+			ei.hyperlink.Reset()
+			ei.hyperlink.WriteString(fields[3])
+		}
+	}
+	return true
+}
+
+// applyMarkupColor returns the given color attribute with its color replaced
+// by the one named in a tag field, keeping the style bits.
+func applyMarkupColor(field string, attr Attribute) (Attribute, bool) {
+	var color Attribute
+	switch field {
+	case "":
+		return attr, true
+	case "-":
+		color = ColorDefault
+	default:
+		var ok bool
+		color, ok = markupColors[strings.ToLower(field)]
+		if !ok {
+			tc := tcell.GetColor(strings.ToLower(field))
+			if tc == tcell.ColorDefault {
+				return attr, false
+			}
+			color = Attribute(tc)
+		}
+	}
+	return attr&AttrStyleBits | color, true
+}
+
+// EscapeMarkup escapes a string so that it is shown literally when written to
+// a view with markup, e.g. because it comes from an untrusted source.
+func EscapeMarkup(s string) string {
+	return strings.ReplaceAll(s, "[", "[[")
+}
+
+// WriteMarkup appends a string with style markup to the view's buffer, even
+// if Markup is not set. A tag has the form [fg:bg:attributes:url], where
+// trailing fields may be omitted:
+//
+//	[red]            red foreground
+//	[yellow:blue]    yellow on blue
+//	[#ff8000::bu]    orange, bold and underlined
+//	[:::https://x]   a hyperlink to https://x
+//
+// An empty field leaves that part of the style unchanged, and "-" resets it,
+// so [-:-:-:-] and its shorthand [-] reset the style completely. Colors are
+// either names (e.g. "red" or "darkorange") or hex values, which need
+// OutputTrue. The attributes are b (bold), d (dim), i (italic), u
+// (underline), l (blink), r (reverse), and s (strikethrough). "[[" stands
+// for a literal "[", and anything in brackets that is not a valid tag is
+// shown as it is. Use EscapeMarkup for text that may contain brackets. Tags
+// can't span lines or calls to Write.
+func (v *View) WriteMarkup(s string) {
+	v.writeMutex.Lock()
+	defer v.writeMutex.Unlock()
+
+	v.writeWithMarkup([]byte(s), true)
+}
@@ -0,0 +1,152 @@
+package gocui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteMarkup(t *testing.T) {
+	orange := NewRGBColor(0xff, 0x80, 0)
+
+	scenarios := []struct {
+		name         string
+		input        string
+		expectedText string
+		// the style of the first cell of each word of the expected text
+		expectedFg        []Attribute
+		expectedBg        []Attribute
+		expectedHyperlink []string
+	}{
+		{
+			name:         "plain text",
+			input:        "plain text",
+			expectedText: "plain text",
+			expectedFg:   []Attribute{ColorDefault, ColorDefault},
+		},
+		{
+			name:         "foreground and reset",
+			input:        "[red::b]error[-] text",
+			expectedText: "error text",
+			expectedFg:   []Attribute{ColorRed | AttrBold, ColorDefault},
+			expectedBg:   []Attribute{ColorDefault, ColorDefault},
+		},
+		{
+			name:         "background",
+			input:        "[yellow:blue]warning [:-]text",
+			expectedText: "warning text",
+			expectedFg:   []Attribute{ColorYellow, ColorYellow},
+			expectedBg:   []Attribute{ColorBlue, ColorDefault},
+		},
+		{
+			name:         "hex color and attributes",
+			input:        "[#ff8000::iu]orange [::-]plain",
+			expectedText: "orange plain",
+			expectedFg:   []Attribute{orange | AttrItalic | AttrUnderline, orange},
+		},
+		{
+			name:              "hyperlink",
+			input:             "[:::https://example.com]link[:::-] text",
+			expectedText:      "link text",
+			expectedFg:        []Attribute{ColorDefault, ColorDefault},
+			expectedHyperlink: []string{"https://example.com", ""},
+		},
+		{
+			name:         "escaped bracket",
+			input:        "[[red] text",
+			expectedText: "[red] text",
+			expectedFg:   []Attribute{ColorDefault, ColorDefault},
+		},
+		{
+			name:         "invalid tags are shown literally",
+			input:        "[x] done [] [:] [::q]",
+			expectedText: "[x] done [] [:] [::q]",
+			expectedFg:   []Attribute{ColorDefault, ColorDefault, ColorDefault, ColorDefault, ColorDefault},
+		},
+		{
+			name:         "unfinished tag",
+			input:        "[red text",
+			expectedText: "[red text",
+			expectedFg:   []Attribute{ColorDefault, ColorDefault},
+		},
+		{
+			name:         "tags don't span lines",
+			input:        "[red\n[green]text",
+			expectedText: "[red\ntext",
+			expectedFg:   []Attribute{ColorDefault, ColorGreen},
+		},
+		{
+			name:         "mixed with ANSI escapes",
+			input:        "\x1b[1m[red]bold[-] plain",
+			expectedText: "bold plain",
+			expectedFg:   []Attribute{ColorRed | AttrBold, ColorDefault},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			v := NewView("name", 0, 0, 20, 20, OutputTrue)
+			v.WriteMarkup(s.input)
+			assert.Equal(t, s.expectedText, v.Buffer())
+
+			var fgs, bgs []Attribute
+			var hyperlinks []string
+			for _, line := range v.lines {
+				for i, c := range line {
+					if c.chr != " " && (i == 0 || line[i-1].chr == " ") {
+						fgs = append(fgs, c.fgColor)
+						bgs = append(bgs, c.bgColor)
+						hyperlinks = append(hyperlinks, c.hyperlink)
+					}
+				}
+			}
+			assert.Equal(t, s.expectedFg, fgs)
+			if s.expectedBg != nil {
+				assert.Equal(t, s.expectedBg, bgs)
+			}
+			if s.expectedHyperlink != nil {
+				assert.Equal(t, s.expectedHyperlink, hyperlinks)
+			}
+		})
+	}
+}
+
+func TestMarkupMode(t *testing.T) {
+	v := NewView("name", 0, 0, 20, 20, OutputNormal)
+	v.SetContent("[green]text")
+	assert.Equal(t, "[green]text", v.Buffer())
+
+	v.Markup = true
+	v.SetContent("[green]text")
+	assert.Equal(t, "text", v.Buffer())
+	assert.Equal(t, ColorGreen, v.lines[0][0].fgColor)
+}
+
+func TestMarkupInEditableView(t *testing.T) {
+	v := NewView("name", 0, 0, 20, 20, OutputNormal)
+	v.Editable = true
+	v.Markup = true
+	v.TextArea.TypeString("a [red]b")
+	v.RenderTextArea()
+	assert.Equal(t, "a [red]b", v.Buffer())
+	cx, _ := v.Cursor()
+	assert.Equal(t, 8, cx)
+
+	v.Highlighter = HighlighterFunc(func(content string) []HighlightSpan {
+		return []HighlightSpan{{Start: 0, End: 1, FgColor: ColorBlue}}
+	})
+	v.RenderTextArea()
+	assert.Equal(t, "a [red]b", v.Buffer())
+	assert.Equal(t, ColorBlue, v.lines[0][0].fgColor)
+}
+
+func TestEscapeMarkup(t *testing.T) {
+	untrusted := "[red]not red[-] [[x]"
+	v := NewView("name", 0, 0, 20, 20, OutputNormal)
+	v.WriteMarkup("[blue]" + EscapeMarkup(untrusted))
+
+	assert.Equal(t, untrusted, v.Buffer())
+	for _, c := range v.lines[0] {
+		assert.Equal(t, ColorBlue, c.fgColor)
+	}
+}
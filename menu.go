@@ -0,0 +1,340 @@
+package gocui
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/rivo/uniseg"
+)
+
+// ContextMenuViewName is the name of the view created by OpenContextMenu.
+// Submenus get the same name followed by their nesting level, e.g.
+// "contextMenu1".
+const ContextMenuViewName = "contextMenu"
+
+const submenuIndicator = "►"
+
+// MenuItem is an entry of a context menu.
+type MenuItem struct {
+	Label string
+	// Shortcut is shown right-aligned next to the label, e.g. "Ctrl+C". It's
+	// just a hint; binding the key is up to the caller.
+	Shortcut string
+	// If Separator is true, the item is drawn as a horizontal line and can't
+	// be selected.
+	Separator bool
+	// Disabled items are drawn dimmed and can't be chosen.
+	Disabled bool
+	// If Submenu is not empty, choosing the item opens a submenu with these
+	// items instead of calling OnSelect.
+	Submenu []*MenuItem
+	// OnSelect is called when the item is chosen, after the menu has been
+	// closed.
+	OnSelect func() error
+}
+
+func (item *MenuItem) selectable() bool {
+	return !item.Separator && !item.Disabled
+}
+
+// contextMenu is one level of an open context menu.
+type contextMenu struct {
+	view     *View
+	items    []*MenuItem
+	selected int
+}
+
+// OpenContextMenu opens a context menu with the given items at the given
+// screen position, typically the position of a right click, i.e. ScreenX and
+// ScreenY of the ViewMouseBindingOpts of a MouseRight binding. The menu is
+// moved to the left and/or up if it wouldn't fit otherwise. It takes the
+// focus, handles the keyboard (Up/Down, Enter/Space, Right/Left for submenus,
+// Esc) and the mouse, and closes when an item is chosen, when the user clicks
+// outside of it, or when the focus moves to another view.
+func (g *Gui) OpenContextMenu(x, y int, items []*MenuItem) error {
+	previousView := g.currentView
+	if len(g.contextMenus) > 0 {
+		previousView = g.contextMenuPreviousView
+		if err := g.closeContextMenus(false); err != nil {
+			return err
+		}
+	}
+	g.contextMenuPreviousView = previousView
+
+	return g.openContextMenuLevel(items, x, y, x)
+}
+
+// CloseContextMenu closes the context menu, including all of its submenus,
+// and gives the focus back to the view that had it before the menu was
+// opened.
+func (g *Gui) CloseContextMenu() error {
+	return g.closeContextMenus(true)
+}
+
+// openContextMenuLevel opens a menu (or submenu) whose top left corner is at
+// the given position. If it doesn't fit to the right of that position, its
+// right edge is aligned with flipX instead.
+func (g *Gui) openContextMenuLevel(items []*MenuItem, x, y, flipX int) error {
+	level := len(g.contextMenus)
+	name := ContextMenuViewName
+	if level > 0 {
+		name += strconv.Itoa(level)
+	}
+
+	menu := &contextMenu{items: items, selected: -1}
+	content, width := menu.render()
+	width = min(width+2, g.maxX)
+	height := min(len(items)+2, g.maxY)
+
+	x0, y0 := x, y
+	if x0+width > g.maxX {
+		x0 = flipX - width + 1
+	}
+	if y0+height > g.maxY {
+		y0 = y - height + 1
+	}
+	x0 = max(0, min(x0, g.maxX-width))
+	y0 = max(0, min(y0, g.maxY-height))
+
+	v, err := g.SetView(name, x0, y0, x0+width-1, y0+height-1, 0)
+	if err != nil && !errors.Is(err, ErrUnknownView) {
+		return err
+	}
+	menu.view = v
+	g.contextMenus = append(g.contextMenus, menu)
+
+	v.Highlight = true
+	v.SetContent(content)
+	menu.selectNext(0, 1)
+
+	v.OnMouseHover = func(_, y int) {
+		if y < len(menu.items) && menu.items[y].selectable() {
+			menu.selectItem(y)
+		}
+	}
+	v.OnBlur = func() {
+		// moving the focus to another level of the menu doesn't close it
+		if !g.isContextMenuView(g.currentView) {
+			_ = g.closeContextMenus(false)
+		}
+	}
+
+	if err := g.setContextMenuBindings(menu, level); err != nil {
+		return err
+	}
+	if _, err := g.SetViewOnTop(name); err != nil {
+		return err
+	}
+	_, err = g.SetCurrentView(name)
+	return err
+}
+
+func (g *Gui) setContextMenuBindings(menu *contextMenu, level int) error {
+	name := menu.view.Name()
+	move := func(direction int) func(*Gui, *View) error {
+		return func(*Gui, *View) error {
+			menu.selectNext(menu.selected+direction, direction)
+			return nil
+		}
+	}
+	choose := func(*Gui, *View) error {
+		return g.chooseContextMenuItem(menu, menu.selected)
+	}
+	openSubmenu := func(*Gui, *View) error {
+		if menu.selected >= 0 && len(menu.items[menu.selected].Submenu) > 0 {
+			return g.chooseContextMenuItem(menu, menu.selected)
+		}
+		return nil
+	}
+	closeLevel := func(*Gui, *View) error {
+		return g.closeContextMenuLevel()
+	}
+
+	for _, binding := range []struct {
+		key     any
+		handler func(*Gui, *View) error
+	}{
+		{KeyArrowUp, move(-1)},
+		{KeyArrowDown, move(1)},
+		{KeyEnter, choose},
+		{KeySpace, choose},
+		{KeyArrowRight, openSubmenu},
+		{KeyEsc, closeLevel},
+	} {
+		if err := g.SetKeybinding(name, binding.key, ModNone, binding.handler); err != nil {
+			return err
+		}
+	}
+	if level > 0 {
+		if err := g.SetKeybinding(name, KeyArrowLeft, ModNone, closeLevel); err != nil {
+			return err
+		}
+	}
+
+	return g.SetViewClickBinding(&ViewMouseBinding{
+		ViewName: name,
+		Key:      MouseLeft,
+		Handler: func(opts ViewMouseBindingOpts) error {
+			if opts.Y < 0 || opts.Y >= len(menu.items) {
+				return nil
+			}
+			// close submenus of other items
+			for g.contextMenus[len(g.contextMenus)-1] != menu {
+				if err := g.closeContextMenuLevel(); err != nil {
+					return err
+				}
+			}
+			return g.chooseContextMenuItem(menu, opts.Y)
+		},
+	})
+}
+
+// chooseContextMenuItem opens the submenu of the item at the given index, or
+// closes the menu and calls the item's OnSelect callback.
+func (g *Gui) chooseContextMenuItem(menu *contextMenu, index int) error {
+	if index < 0 || index >= len(menu.items) || !menu.items[index].selectable() {
+		return nil
+	}
+	menu.selectItem(index)
+
+	item := menu.items[index]
+	if len(item.Submenu) > 0 {
+		v := menu.view
+		itemY := v.contentY0() + index - v.oy
+		return g.openContextMenuLevel(item.Submenu, v.x1, itemY-1, v.x0)
+	}
+
+	if err := g.closeContextMenus(true); err != nil {
+		return err
+	}
+	if item.OnSelect != nil {
+		return item.OnSelect()
+	}
+	return nil
+}
+
+// closeContextMenuLevel closes the innermost submenu and gives the focus back
+// to its parent menu, or closes the whole menu if there is no submenu.
+func (g *Gui) closeContextMenuLevel() error {
+	if len(g.contextMenus) <= 1 {
+		return g.closeContextMenus(true)
+	}
+
+	menu := g.contextMenus[len(g.contextMenus)-1]
+	g.contextMenus = g.contextMenus[:len(g.contextMenus)-1]
+	if err := g.deleteContextMenuView(menu.view); err != nil {
+		return err
+	}
+
+	_, err := g.SetCurrentView(g.contextMenus[len(g.contextMenus)-1].view.Name())
+	return err
+}
+
+// closeContextMenus closes all levels of the context menu. If restoreFocus is
+// true, the focus goes back to the view that had it before the menu was
+// opened.
+func (g *Gui) closeContextMenus(restoreFocus bool) error {
+	if len(g.contextMenus) == 0 {
+		return nil
+	}
+
+	menus := g.contextMenus
+	g.contextMenus = nil
+	for _, menu := range slices.Backward(menus) {
+		if err := g.deleteContextMenuView(menu.view); err != nil {
+			return err
+		}
+	}
+
+	previousView := g.contextMenuPreviousView
+	g.contextMenuPreviousView = nil
+	if restoreFocus && previousView != nil {
+		if _, err := g.SetCurrentView(previousView.Name()); err != nil && !errors.Is(err, ErrUnknownView) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *Gui) deleteContextMenuView(v *View) error {
+	// we're closing the menu ourselves, so we don't want to be told about
+	// losing the focus
+	v.OnBlur = nil
+
+	g.DeleteViewKeybindings(v.Name())
+	g.viewMouseBindings = deleteViewMouseBindings(g.viewMouseBindings, v.Name())
+	if err := g.DeleteView(v.Name()); err != nil && !errors.Is(err, ErrUnknownView) {
+		return err
+	}
+	return nil
+}
+
+func (g *Gui) isContextMenuView(v *View) bool {
+	return slices.ContainsFunc(g.contextMenus, func(menu *contextMenu) bool {
+		return menu.view == v
+	})
+}
+
+// selectNext selects the first selectable item starting at the given index
+// and moving in the given direction, wrapping around at either end.
+func (menu *contextMenu) selectNext(index int, direction int) {
+	for range menu.items {
+		index = (index + len(menu.items)) % len(menu.items)
+		if menu.items[index].selectable() {
+			menu.selectItem(index)
+			return
+		}
+		index += direction
+	}
+}
+
+func (menu *contextMenu) selectItem(index int) {
+	menu.selected = index
+	menu.view.FocusPoint(0, index, true)
+}
+
+// render returns the content of the menu view and its width without the
+// frame.
+func (menu *contextMenu) render() (string, int) {
+	labelWidth, shortcutWidth := 0, 0
+	for _, item := range menu.items {
+		labelWidth = max(labelWidth, uniseg.StringWidth(item.Label))
+		shortcutWidth = max(shortcutWidth, uniseg.StringWidth(item.shortcutHint()))
+	}
+
+	width := labelWidth + 2
+	if shortcutWidth > 0 {
+		width += shortcutWidth + 2
+	}
+
+	lines := make([]string, 0, len(menu.items))
+	for _, item := range menu.items {
+		if item.Separator {
+			lines = append(lines, strings.Repeat("─", width))
+			continue
+		}
+
+		line := " " + item.Label
+		if shortcutWidth > 0 {
+			shortcut := item.shortcutHint()
+			line += strings.Repeat(" ", labelWidth-uniseg.StringWidth(item.Label)+2+shortcutWidth-uniseg.StringWidth(shortcut))
+			line += shortcut
+		}
+		line += " "
+		if item.Disabled {
+			line = "\x1b[2m" + line + "\x1b[0m"
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n"), width
+}
+
+func (item *MenuItem) shortcutHint() string {
+	if len(item.Submenu) > 0 {
+		return submenuIndicator
+	}
+	return item.Shortcut
+}
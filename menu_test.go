@@ -0,0 +1,134 @@
+package gocui
+
+import (
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextMenu(t *testing.T) {
+	g := &Gui{maxX: 40, maxY: 20}
+	mainView, _ := g.SetView("main", 0, 0, 39, 19, 0)
+	g.currentView = mainView
+
+	var chosen []string
+	item := func(label string) *MenuItem {
+		return &MenuItem{Label: label, OnSelect: func() error {
+			chosen = append(chosen, label)
+			return nil
+		}}
+	}
+	items := []*MenuItem{
+		{Label: "Cut", Disabled: true},
+		{Label: "Copy", Shortcut: "Ctrl+C", OnSelect: item("Copy").OnSelect},
+		{Separator: true},
+		{Label: "More", Submenu: []*MenuItem{item("Sub 1"), item("Sub 2")}},
+		item("Delete"),
+	}
+
+	press := func(key Key) {
+		assert.NoError(t, g.execKeybindings(g.currentView, &GocuiEvent{Type: eventKey, Key: key}))
+	}
+
+	// near the bottom right corner the menu flips to the left and up
+	assert.NoError(t, g.OpenContextMenu(35, 18, items))
+	v, err := g.View(ContextMenuViewName)
+	assert.NoError(t, err)
+	assert.Equal(t, v, g.CurrentView())
+	x0, y0, x1, y1 := v.Dimensions()
+	assert.Equal(t, []int{18, 12, 35, 18}, []int{x0, y0, x1, y1})
+	assert.Equal(t, ""+
+		" Cut            \n"+
+		" Copy    Ctrl+C \n"+
+		"────────────────\n"+
+		" More         ► \n"+
+		" Delete         ", v.Buffer())
+	assert.NotZero(t, v.lines[0][1].fgColor&AttrDim)
+	assert.Zero(t, v.lines[1][1].fgColor&AttrDim)
+
+	// disabled items and separators are skipped
+	menu := g.contextMenus[0]
+	assert.Equal(t, 1, menu.selected)
+	press(KeyArrowDown)
+	assert.Equal(t, 3, menu.selected)
+	press(KeyArrowDown)
+	press(KeyArrowDown)
+	assert.Equal(t, 1, menu.selected)
+	press(KeyArrowUp)
+	assert.Equal(t, 4, menu.selected)
+
+	// submenus open next to the item, flipped to the left of the menu
+	press(KeyArrowUp)
+	press(KeyArrowRight)
+	sub, err := g.View(ContextMenuViewName + "1")
+	assert.NoError(t, err)
+	assert.Equal(t, sub, g.CurrentView())
+	x0, y0, x1, y1 = sub.Dimensions()
+	assert.Equal(t, []int{10, 15, 18, 18}, []int{x0, y0, x1, y1})
+
+	press(KeyArrowLeft)
+	assert.Equal(t, v, g.CurrentView())
+	_, err = g.View(ContextMenuViewName + "1")
+	assert.True(t, errors.Is(err, ErrUnknownView))
+
+	press(KeyEnter)
+	press(KeyArrowDown)
+	press(KeyEnter)
+	assert.Equal(t, []string{"Sub 2"}, chosen)
+	assert.Equal(t, mainView, g.CurrentView())
+	assert.Empty(t, g.contextMenus)
+	_, err = g.View(ContextMenuViewName)
+	assert.True(t, errors.Is(err, ErrUnknownView))
+
+	// clicking an item chooses it; clicks on disabled items are ignored
+	assert.NoError(t, g.OpenContextMenu(2, 2, items))
+	v, _ = g.View(ContextMenuViewName)
+	x0, y0, _, _ = v.Dimensions()
+	assert.Equal(t, []int{2, 2}, []int{x0, y0})
+	click := func(y int) {
+		_, err := g.execMouseKeybindings(v, &GocuiEvent{Type: eventMouse, Key: MouseLeft}, ViewMouseBindingOpts{Y: y, Key: MouseLeft})
+		assert.NoError(t, err)
+	}
+	click(0)
+	assert.Equal(t, v, g.CurrentView())
+	click(4)
+	assert.Equal(t, []string{"Sub 2", "Delete"}, chosen)
+	assert.Equal(t, mainView, g.CurrentView())
+
+	// moving the focus away closes the menu
+	assert.NoError(t, g.OpenContextMenu(2, 2, items))
+	press(KeyArrowDown)
+	press(KeyEnter)
+	assert.Len(t, g.contextMenus, 2)
+	_, err = g.SetCurrentView("main")
+	assert.NoError(t, err)
+	assert.Empty(t, g.contextMenus)
+	assert.Len(t, g.views, 1)
+	assert.Empty(t, g.viewMouseBindings)
+}
+
+func TestContextMenuAtRightClick(t *testing.T) {
+	g := &Gui{maxX: 40, maxY: 20}
+	mainView, _ := g.SetView("main", 5, 3, 39, 19, 0)
+	mainView.SetContent("a\nb\nc\nd\ne\nf")
+	mainView.SetOrigin(0, 2)
+	g.currentView = mainView
+
+	var clicked ViewMouseBindingOpts
+	assert.NoError(t, g.SetViewClickBinding(&ViewMouseBinding{
+		ViewName: "main",
+		Key:      MouseRight,
+		Handler: func(opts ViewMouseBindingOpts) error {
+			clicked = opts
+			return g.OpenContextMenu(opts.ScreenX, opts.ScreenY, []*MenuItem{{Label: "Copy"}})
+		},
+	}))
+
+	assert.NoError(t, g.onKey(&GocuiEvent{Type: eventMouse, Key: MouseRight, MouseX: 12, MouseY: 8}))
+	assert.Equal(t, []int{6, 6, 12, 8}, []int{clicked.X, clicked.Y, clicked.ScreenX, clicked.ScreenY})
+	v, err := g.View(ContextMenuViewName)
+	assert.NoError(t, err)
+	x0, y0, _, _ := v.Dimensions()
+	assert.Equal(t, []int{12, 8}, []int{x0, y0})
+}
@@ -0,0 +1,45 @@
+package gocui
+
+// padding returns the padding of the given side of the view, which is one of
+// TOP, BOTTOM, LEFT and RIGHT.
+func (v *View) padding(side byte) int {
+	switch side {
+	case TOP:
+		return max(v.PaddingTop, 0)
+	case BOTTOM:
+		return max(v.PaddingBottom, 0)
+	case LEFT:
+		return max(v.PaddingLeft, 0)
+	case RIGHT:
+		return max(v.PaddingRight, 0)
+	}
+	return 0
+}
+
+// contentInset returns the number of cells between the given side of the
+// view and its content, i.e. the frame plus the padding.
+func (v *View) contentInset(side byte) int {
+	return v.frameInset(side) + v.padding(side)
+}
+
+// contentY0 returns the screen row of the first row of the view's content.
+func (v *View) contentY0() int {
+	return v.y0 + v.contentInset(TOP)
+}
+
+// clearPadding fills the padding of the view with blank cells.
+func (v *View) clearPadding() {
+	if v.padding(TOP)+v.padding(BOTTOM)+v.padding(LEFT)+v.padding(RIGHT) == 0 {
+		return
+	}
+
+	x0, x1 := v.x0+v.frameInset(LEFT), v.x1-v.frameInset(RIGHT)
+	y0, y1 := v.y0+v.frameInset(TOP), v.y1-v.frameInset(BOTTOM)
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			if x < x0+v.padding(LEFT) || x > x1-v.padding(RIGHT) || y < y0+v.padding(TOP) || y > y1-v.padding(BOTTOM) {
+				tcellSetCell(x, y, " ", v.FgColor, v.BgColor, v.outMode)
+			}
+		}
+	}
+}
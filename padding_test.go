@@ -0,0 +1,78 @@
+package gocui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrawWithPadding(t *testing.T) {
+	setupSimulationScreen(t, 20, 10)
+	g := &Gui{maxX: 20, maxY: 10, Cursor: true}
+
+	v, _ := g.SetView("v", 0, 0, 9, 5, 0)
+	v.PaddingLeft, v.PaddingRight, v.PaddingTop = 2, 1, 1
+	v.Wrap = true
+	v.SetContent("hello world")
+
+	w, h := v.InnerSize()
+	assert.Equal(t, 5, w)
+	assert.Equal(t, 3, h)
+
+	g.currentView = v
+	v.SetCursor(1, 1)
+	assert.NoError(t, g.draw(v))
+	assert.Equal(t, []string{
+		"┌────────┐",
+		"│        │",
+		"│  hello │",
+		"│  world │",
+		"│        │",
+		"└────────┘",
+	}, []string{
+		screenRow(0, 9, 0), screenRow(0, 9, 1), screenRow(0, 9, 2),
+		screenRow(0, 9, 3), screenRow(0, 9, 4), screenRow(0, 9, 5),
+	})
+
+	x, y, visible := Screen.(tcell.SimulationScreen).GetCursor()
+	assert.True(t, visible)
+	assert.Equal(t, 4, x)
+	assert.Equal(t, 3, y)
+}
+
+func TestScrollbarWithPadding(t *testing.T) {
+	g := &Gui{maxX: 20, maxY: 20}
+	v, _ := g.SetView("v", 0, 0, 9, 7, 0)
+	v.PaddingTop, v.PaddingBottom = 1, 1
+	v.SetContent(strings.Repeat("line\n", 20))
+
+	// the scrollbar covers the rows of the content
+	show, start, _ := calcRealScrollbarStartEnd(v)
+	assert.True(t, show)
+	assert.Equal(t, 2, start)
+}
+
+func TestMouseCoordinatesWithPadding(t *testing.T) {
+	g := &Gui{maxX: 40, maxY: 20}
+	v, _ := g.SetView("v", 0, 0, 20, 10, 0)
+	v.PaddingLeft, v.PaddingTop = 2, 1
+	v.SetContent("first\nsecond")
+
+	var clicked ViewMouseBindingOpts
+	assert.NoError(t, g.SetViewClickBinding(&ViewMouseBinding{
+		ViewName: "v",
+		Key:      MouseLeft,
+		Handler: func(opts ViewMouseBindingOpts) error {
+			clicked = opts
+			return nil
+		},
+	}))
+
+	assert.NoError(t, g.onKey(&GocuiEvent{Type: eventMouse, Key: MouseLeft, MouseX: 5, MouseY: 3}))
+	assert.Equal(t, 2, clicked.X)
+	assert.Equal(t, 1, clicked.Y)
+	assert.Equal(t, 2, v.CursorX())
+	assert.Equal(t, 1, v.CursorY())
+}
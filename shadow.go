@@ -0,0 +1,138 @@
+package gocui
+
+import (
+	"slices"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// BackdropMode selects how the views beneath a view are shown while it is
+// visible, e.g. to set a modal popup apart from the rest of the screen.
+type BackdropMode int
+
+const (
+	BackdropNone BackdropMode = iota
+	// BackdropDim draws the views beneath in dimmed colors.
+	BackdropDim
+	// BackdropDesaturate draws the views beneath in dimmed shades of gray.
+	BackdropDesaturate
+)
+
+// drawBackdrop restyles the views that were drawn before the given view,
+// according to its backdrop mode.
+func (g *Gui) drawBackdrop(v *View) {
+	restyle := backdropRestyler(v.Backdrop)
+	if restyle == nil {
+		return
+	}
+
+	for _, below := range g.views {
+		if below == v {
+			break
+		}
+		g.restyleView(below, restyle)
+	}
+}
+
+// drawEffectsAbove draws the backdrops and shadows of the views above the
+// given views again, after the latter were redrawn on their own, e.g. by
+// ForceRedrawViews.
+func (g *Gui) drawEffectsAbove(views []*View) {
+	for _, v := range views {
+		idx := slices.Index(g.views, v)
+		if idx == -1 {
+			continue
+		}
+		for _, above := range g.views[idx+1:] {
+			if !above.Visible {
+				continue
+			}
+			if restyle := backdropRestyler(above.Backdrop); restyle != nil {
+				g.restyleView(v, restyle)
+			}
+			if above.Shadow {
+				g.drawShadow(above)
+			}
+		}
+	}
+}
+
+// backdropRestyler returns the function that restyles the cells beneath a
+// view with the given backdrop mode, or nil if there is no backdrop.
+func backdropRestyler(mode BackdropMode) func(tcell.Style) tcell.Style {
+	switch mode {
+	case BackdropDim:
+		return func(style tcell.Style) tcell.Style {
+			return style.Dim(true)
+		}
+	case BackdropDesaturate:
+		return func(style tcell.Style) tcell.Style {
+			fg, bg, _ := style.Decompose()
+			return style.Foreground(desaturate(fg)).Background(desaturate(bg)).Dim(true)
+		}
+	default:
+		return nil
+	}
+}
+
+// restyleView restyles all cells of the given view on the screen.
+func (g *Gui) restyleView(v *View, restyle func(tcell.Style) tcell.Style) {
+	if !v.Visible {
+		return
+	}
+	for y := max(v.y0, 0); y <= v.y1 && y < g.maxY; y++ {
+		for x := max(v.x0, 0); x <= v.x1 && x < g.maxX; x++ {
+			restyleScreenCell(x, y, restyle)
+		}
+	}
+}
+
+// drawShadow draws the shadow of a view on the cells to its right and below
+// it.
+func (g *Gui) drawShadow(v *View) {
+	shadowColor := g.ShadowColor
+	if shadowColor == ColorDefault {
+		shadowColor = ColorBlack
+	}
+	restyle := func(style tcell.Style) tcell.Style {
+		return style.Background(getTcellColor(shadowColor, g.outputMode)).Dim(true)
+	}
+
+	for y := max(v.y0+1, 0); y <= v.y1+1 && y < g.maxY; y++ {
+		if x := v.x1 + 1; x >= 0 && x < g.maxX {
+			restyleScreenCell(x, y, restyle)
+		}
+	}
+	if y := v.y1 + 1; y >= 0 && y < g.maxY {
+		for x := max(v.x0+1, 0); x <= v.x1 && x < g.maxX; x++ {
+			restyleScreenCell(x, y, restyle)
+		}
+	}
+}
+
+// restyleScreenCell changes the style of a cell that is already on the
+// screen, keeping its character. The second half of a wide character is left
+// alone.
+func restyleScreenCell(x, y int, restyle func(tcell.Style) tcell.Style) {
+	if x > 0 {
+		if _, _, width := Screen.Get(x-1, y); width > 1 {
+			return
+		}
+	}
+	str, style, _ := Screen.Get(x, y)
+	Screen.Put(x, y, str, restyle(style))
+}
+
+// desaturate returns the shade of gray with the same luminance as the given
+// color. The default color is left alone, since we don't know what it is.
+func desaturate(c tcell.Color) tcell.Color {
+	if c == tcell.ColorDefault || !c.Valid() {
+		return c
+	}
+	r, g, b := c.RGB()
+	if r < 0 {
+		return c
+	}
+	l := (299*r + 587*g + 114*b) / 1000
+	return tcell.NewRGBColor(l, l, l)
+}
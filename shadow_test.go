@@ -0,0 +1,137 @@
+package gocui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func screenStyle(x, y int) tcell.Style {
+	_, style, _ := Screen.Get(x, y)
+	return style
+}
+
+func TestViewShadow(t *testing.T) {
+	setupSimulationScreen(t, 20, 10)
+	g := &Gui{maxX: 20, maxY: 10}
+
+	bottom, _ := g.SetView("bottom", 0, 0, 19, 9, 0)
+	bottom.Frame = false
+	bottom.SetContent("abcdefghijklmnopqr\nabcdefghijklmnopqr\nabcdefghijklmnopqr\nabcdefghijklmnopqr\nabcdefghijklmnopqr")
+	popup, _ := g.SetView("popup", 2, 0, 8, 3, 0)
+	popup.Shadow = true
+
+	assert.NoError(t, g.draw(bottom))
+	assert.NoError(t, g.draw(popup))
+
+	shadowed := [][2]int{{9, 1}, {9, 4}, {3, 4}, {8, 4}}
+	for _, pos := range shadowed {
+		fg, bg, attrs := screenStyle(pos[0], pos[1]).Decompose()
+		assert.Equal(t, tcell.ColorBlack, bg, "cell %v", pos)
+		assert.NotZero(t, attrs&tcell.AttrDim, "cell %v", pos)
+		assert.Equal(t, tcell.ColorDefault, fg, "cell %v", pos)
+	}
+	// the shadow keeps the characters underneath it
+	assert.Equal(t, "i", screenRow(9, 9, 1))
+
+	notShadowed := [][2]int{{9, 0}, {2, 4}, {10, 2}, {5, 5}}
+	for _, pos := range notShadowed {
+		_, bg, attrs := screenStyle(pos[0], pos[1]).Decompose()
+		assert.Equal(t, tcell.ColorDefault, bg, "cell %v", pos)
+		assert.Zero(t, attrs&tcell.AttrDim, "cell %v", pos)
+	}
+
+	g.ShadowColor = ColorBlue
+	assert.NoError(t, g.draw(popup))
+	_, bg, _ := screenStyle(9, 2).Decompose()
+	assert.Equal(t, tcell.ColorNavy, bg)
+}
+
+func TestViewShadowWideCharacters(t *testing.T) {
+	setupSimulationScreen(t, 20, 10)
+	g := &Gui{maxX: 20, maxY: 10}
+
+	bottom, _ := g.SetView("bottom", 0, 0, 19, 9, 0)
+	bottom.Frame = false
+	bottom.SetContent("\n\n\n\n中中中中中中")
+	popup, _ := g.SetView("popup", 2, 0, 6, 3, 0)
+	popup.Shadow = true
+
+	assert.NoError(t, g.draw(bottom))
+	assert.NoError(t, g.draw(popup))
+
+	assert.Equal(t, "中 中 中 中 中 中 ", screenRow(1, 12, 5))
+}
+
+func TestViewBackdrop(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     BackdropMode
+		expected tcell.Color
+	}{
+		{name: "dim", mode: BackdropDim, expected: tcell.NewRGBColor(255, 0, 0)},
+		{name: "desaturate", mode: BackdropDesaturate, expected: tcell.NewRGBColor(76, 76, 76)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setupSimulationScreen(t, 20, 10)
+			g := &Gui{maxX: 20, maxY: 10, outputMode: OutputTrue}
+
+			bottom, _ := g.SetView("bottom", 0, 0, 19, 9, 0)
+			bottom.FgColor = NewRGBColor(255, 0, 0)
+			bottom.SetContent("abc")
+			modal, _ := g.SetView("modal", 5, 3, 15, 6, 0)
+			modal.Backdrop = test.mode
+			modal.SetContent("modal")
+
+			assert.NoError(t, g.draw(bottom))
+			assert.NoError(t, g.draw(modal))
+
+			fg, _, attrs := screenStyle(1, 1).Decompose()
+			assert.Equal(t, test.expected, fg)
+			assert.NotZero(t, attrs&tcell.AttrDim)
+			assert.Equal(t, "abc", screenRow(1, 3, 1))
+
+			_, _, attrs = screenStyle(6, 4).Decompose()
+			assert.Zero(t, attrs&tcell.AttrDim)
+			assert.Equal(t, "modal", screenRow(6, 10, 4))
+
+			// redrawing the underlying view restores its normal style
+			assert.NoError(t, g.draw(bottom))
+			_, _, attrs = screenStyle(1, 1).Decompose()
+			assert.Zero(t, attrs&tcell.AttrDim)
+		})
+	}
+}
+
+func TestForceRedrawViewBeneathModal(t *testing.T) {
+	setupSimulationScreen(t, 20, 10)
+	g := &Gui{maxX: 20, maxY: 10}
+
+	bottom, _ := g.SetView("bottom", 0, 0, 19, 9, 0)
+	bottom.Frame = false
+	bottom.SetContent("spinner |\n\n\nabcdefghijklmnopqr")
+	modal, _ := g.SetView("modal", 2, 0, 8, 2, 0)
+	modal.Backdrop = BackdropDim
+	modal.Shadow = true
+
+	assert.NoError(t, g.draw(bottom))
+	assert.NoError(t, g.draw(modal))
+
+	bottom.SetContent("spinner /\n\n\nabcdefghijklmnopqr")
+	assert.NoError(t, g.ForceRedrawViews(bottom))
+
+	// the redrawn view is still dimmed
+	_, _, attrs := screenStyle(12, 4).Decompose()
+	assert.NotZero(t, attrs&tcell.AttrDim)
+
+	// and the modal's shadow is still there
+	_, bg, _ := screenStyle(9, 1).Decompose()
+	assert.Equal(t, tcell.ColorBlack, bg)
+	_, bg, _ = screenStyle(5, 3).Decompose()
+	assert.Equal(t, tcell.ColorBlack, bg)
+	_, bg, _ = screenStyle(12, 4).Decompose()
+	assert.Equal(t, tcell.ColorDefault, bg)
+}
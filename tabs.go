@@ -0,0 +1,191 @@
+package gocui
+
+import "github.com/rivo/uniseg"
+
+const (
+	tabSeparator       = " - "
+	tabScrollLeftRune  = "◄"
+	tabScrollRightRune = "►"
+	ellipsis           = "…"
+)
+
+// titleCell is a single grapheme cluster in the title of a view, together with
+// the information we need to style it and to handle clicks on it.
+type titleCell struct {
+	chr   string
+	width int
+	// the index of the tab that is selected when clicking this cell, or -1
+	tabIndex int
+	// true if the cell is part of the currently selected tab
+	selected bool
+}
+
+// titlePrefix returns the prefix that is drawn before the title or tabs of the
+// view, including the frame rune that separates it from the title.
+func (v *View) titlePrefix() string {
+	if v.TitlePrefix == "" {
+		return ""
+	}
+
+	if len(v.FrameRunes) > 0 {
+		return v.TitlePrefix + string(v.FrameRunes[0])
+	}
+	return v.TitlePrefix + "─"
+}
+
+// titleCells returns the cells of the view's title (or tabs) as they are drawn
+// after the title prefix, given the number of columns that are available.
+func (v *View) titleCells(availableWidth int) []titleCell {
+	if len(v.Tabs) == 0 {
+		var cells []titleCell
+		appendTitleCells(&cells, v.Title, -1, false)
+		return cells
+	}
+
+	return v.tabCells(availableWidth)
+}
+
+// tabLabel returns the text that is shown for the tab at the given index,
+// including its badge.
+func (v *View) tabLabel(index int) string {
+	if index < len(v.TabBadges) && v.TabBadges[index] != "" {
+		return v.Tabs[index] + " " + v.TabBadges[index]
+	}
+	return v.Tabs[index]
+}
+
+func (v *View) tabCells(availableWidth int) []titleCell {
+	first, last := v.visibleTabRange(availableWidth)
+
+	var cells []titleCell
+	if first > 0 {
+		appendTitleCells(&cells, tabScrollLeftRune, first-1, false)
+		appendTitleCells(&cells, " ", -1, false)
+	}
+	for i := first; i <= last; i++ {
+		appendTitleCells(&cells, v.tabLabel(i), i, i == v.TabIndex)
+		if i < last {
+			appendTitleCells(&cells, tabSeparator, -1, false)
+		}
+	}
+	if last < len(v.Tabs)-1 {
+		appendTitleCells(&cells, " ", -1, false)
+		appendTitleCells(&cells, tabScrollRightRune, last+1, false)
+	}
+
+	return truncateTitleCells(cells, availableWidth)
+}
+
+// visibleTabRange returns the indices of the first and last tab that fit into
+// the given width, always including the selected tab. If not all tabs fit, we
+// reserve space for the scroll arrows.
+func (v *View) visibleTabRange(availableWidth int) (int, int) {
+	widths := make([]int, len(v.Tabs))
+	for i := range v.Tabs {
+		widths[i] = uniseg.StringWidth(v.tabLabel(i))
+	}
+	separatorWidth := uniseg.StringWidth(tabSeparator)
+	arrowWidth := uniseg.StringWidth(tabScrollLeftRune) + 1
+
+	usedWidth := func(first, last int) int {
+		width := (last - first) * separatorWidth
+		for i := first; i <= last; i++ {
+			width += widths[i]
+		}
+		if first > 0 {
+			width += arrowWidth
+		}
+		if last < len(v.Tabs)-1 {
+			width += arrowWidth
+		}
+		return width
+	}
+
+	selected := max(0, min(v.TabIndex, len(v.Tabs)-1))
+	first, last := selected, selected
+	for {
+		grew := false
+		if last < len(v.Tabs)-1 && usedWidth(first, last+1) <= availableWidth {
+			last++
+			grew = true
+		}
+		if first > 0 && usedWidth(first-1, last) <= availableWidth {
+			first--
+			grew = true
+		}
+		if !grew {
+			return first, last
+		}
+	}
+}
+
+func appendTitleCells(cells *[]titleCell, str string, tabIndex int, selected bool) {
+	for _, chr := range stringToGraphemes(str) {
+		*cells = append(*cells, titleCell{
+			chr:      chr,
+			width:    uniseg.StringWidth(chr),
+			tabIndex: tabIndex,
+			selected: selected,
+		})
+	}
+}
+
+// truncateTitleCells cuts off cells that don't fit into the given width,
+// ending the title with an ellipsis
+func truncateTitleCells(cells []titleCell, availableWidth int) []titleCell {
+	width := 0
+	for _, c := range cells {
+		width += c.width
+	}
+	if width <= availableWidth || availableWidth < 1 {
+		return cells
+	}
+
+	width = 0
+	for i, c := range cells {
+		if width+c.width > availableWidth-1 {
+			last := titleCell{chr: ellipsis, width: 1, tabIndex: c.tabIndex, selected: c.selected}
+			return append(cells[:i:i], last)
+		}
+		width += c.width
+	}
+	return cells
+}
+
+// GetClickedTabIndex tells us which tab was clicked, given the x position of
+// the click relative to the left edge of the view. Returns -1 if the click
+// wasn't on a tab.
+func (v *View) GetClickedTabIndex(x int) int {
+	if len(v.Tabs) <= 1 {
+		return 0
+	}
+
+	col := 2 + uniseg.StringWidth(v.titlePrefix())
+	for _, c := range v.titleCells(v.Width() - 2 - col) {
+		if x >= col && x < col+c.width {
+			return c.tabIndex
+		}
+		col += c.width
+	}
+
+	return -1
+}
+
+// selectTab switches the given view to the tab with the given index, wrapping
+// around at either end. If a tab click binding is registered for the view, it
+// is responsible for switching tabs; otherwise we just update the TabIndex.
+func (g *Gui) selectTab(v *View, index int) error {
+	if len(v.Tabs) == 0 {
+		return nil
+	}
+
+	index = (index + len(v.Tabs)) % len(v.Tabs)
+	for _, binding := range g.tabClickBindings {
+		if binding.viewName == v.Name() {
+			return binding.handler(index)
+		}
+	}
+
+	v.TabIndex = index
+	return nil
+}
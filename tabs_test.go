@@ -0,0 +1,106 @@
+package gocui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetClickedTabIndex(t *testing.T) {
+	tests := []struct {
+		name        string
+		width       int
+		titlePrefix string
+		tabs        []string
+		badges      []string
+		tabIndex    int
+		// the tab index expected for clicks at x = 0, 1, 2, ...
+		expected []int
+	}{
+		{
+			name:     "ASCII tabs",
+			width:    20,
+			tabs:     []string{"ab", "cd"},
+			expected: []int{-1, -1, 0, 0, -1, -1, -1, 1, 1, -1},
+		},
+		{
+			name:     "Wide characters",
+			width:    20,
+			tabs:     []string{"日本", "cd"},
+			expected: []int{-1, -1, 0, 0, 0, 0, -1, -1, -1, 1, 1, -1},
+		},
+		{
+			name:        "Non-ASCII title prefix",
+			width:       20,
+			titlePrefix: "ü",
+			tabs:        []string{"ab", "cd"},
+			expected:    []int{-1, -1, -1, -1, 0, 0, -1, -1, -1, 1, 1, -1},
+		},
+		{
+			name:     "Badges",
+			width:    20,
+			tabs:     []string{"ab", "cd"},
+			badges:   []string{"3"},
+			expected: []int{-1, -1, 0, 0, 0, 0, -1, -1, -1, 1, 1, -1},
+		},
+		{
+			name:     "Overflow to the right",
+			width:    14,
+			tabs:     []string{"aa", "bb", "cc", "dd"},
+			tabIndex: 0,
+			// "aa - bb ►"
+			expected: []int{-1, -1, 0, 0, -1, -1, -1, 1, 1, -1, 2, -1, -1, -1},
+		},
+		{
+			name:     "Overflow on both sides",
+			width:    14,
+			tabs:     []string{"aa", "bb", "cc", "dd", "ee"},
+			tabIndex: 2,
+			// "◄ bb - cc ►" doesn't fit, so it's "◄ cc ►"
+			expected: []int{-1, -1, 1, -1, 2, 2, -1, 3, -1, -1, -1, -1, -1, -1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := NewView("name", 0, 0, test.width-1, 5, OutputNormal)
+			v.TitlePrefix = test.titlePrefix
+			v.Tabs = test.tabs
+			v.TabBadges = test.badges
+			v.TabIndex = test.tabIndex
+
+			actual := make([]int, len(test.expected))
+			for x := range test.expected {
+				actual[x] = v.GetClickedTabIndex(x)
+			}
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestTabKeys(t *testing.T) {
+	g := &Gui{maxX: 40, maxY: 20, NextTabKey: ']', PrevTabKey: '['}
+	v, _ := g.SetView("v", 0, 0, 39, 19, 0)
+	v.Tabs = []string{"a", "b", "c"}
+	g.currentView = v
+
+	press := func(ch rune) {
+		assert.NoError(t, g.execKeybindings(v, &GocuiEvent{Type: eventKey, Ch: ch}))
+	}
+
+	press(']')
+	assert.Equal(t, 1, v.TabIndex)
+	press('[')
+	press('[')
+	assert.Equal(t, 2, v.TabIndex)
+
+	// keybindings take precedence over the tab keys
+	called := false
+	assert.NoError(t, g.SetKeybinding("v", ']', ModNone, func(*Gui, *View) error {
+		called = true
+		return nil
+	}))
+	press(']')
+	assert.True(t, called)
+	assert.Equal(t, 2, v.TabIndex)
+}
@@ -149,7 +149,9 @@ type gocuiEventType uint8
 
 // GocuiEvent represents events like a keys, mouse actions, or window resize.
 //
-//	The 'Mod', 'Key' and 'Ch' fields are valid if 'Type' is 'eventKey'.
+//	The 'Mod', 'Key' and 'Ch' fields are valid if 'Type' is 'eventKey'. 'Mod'
+//	  holds all modifiers that were pressed together with the key, except for
+//	  those already implied by it (ctrl for the KeyCtrl* keys, shift for runes).
 //	The 'MouseX' and 'MouseY' fields are valid if 'Type' is 'eventMouse'.
 //	The 'Width' and 'Height' fields are valid if 'Type' is 'eventResize'.
 //	The 'Focused' field is valid if 'Type' is 'eventFocus'.
@@ -298,30 +300,21 @@ func (g *Gui) pollEvent() GocuiEvent {
 			}
 		}
 		mod := tev.Modifiers()
-		// remove control modifier and setup special handling of ctrl+spacebar, etc.
-		if mod == tcell.ModCtrl && k == 32 {
-			mod = 0
-			ch = rune(0)
+		switch {
+		case k == 32 && mod&tcell.ModCtrl != 0:
+			// special handling of ctrl+spacebar
 			k = tcell.KeyCtrlSpace
-		} else if mod == tcell.ModShift && k == tcell.KeyUp {
-			mod = 0
-			ch = rune(0)
-			k = tcell.KeyF62
-		} else if mod == tcell.ModShift && k == tcell.KeyDown {
-			mod = 0
-			ch = rune(0)
-			k = tcell.KeyF63
-		} else if mod == tcell.ModCtrl || mod == tcell.ModShift {
-			// remove Ctrl or Shift if specified
-			// - shift - will be translated to the final code of rune
-			// - ctrl  - is translated in the key
-			mod = 0
-		} else if mod == tcell.ModAlt && k == tcell.KeyEnter {
-			// for the sake of convenience I'm having a KeyAltEnter key. I will likely
-			// regret this laziness in the future. We're arbitrarily mapping that to tcell's
-			// KeyF64.
-			mod = 0
-			k = tcell.KeyF64
+			mod &^= tcell.ModCtrl
+		case k >= tcell.KeyCtrlSpace && k <= tcell.KeyCtrlUnderscore &&
+			k != tcell.KeyEnter && k != tcell.KeyTab && k != tcell.KeyBackspace && k != tcell.KeyEsc:
+			// ctrl is already implied by the key. Enter, tab, backspace and
+			// escape are excluded explicitly, since they can be combined with
+			// ctrl (e.g. ctrl+enter) and traditionally share their codes with
+			// control keys
+			mod &^= tcell.ModCtrl
+		case ch != 0:
+			// shift is already reflected in the rune
+			mod &^= tcell.ModShift
 		}
 
 		return GocuiEvent{
@@ -359,6 +352,7 @@ func (g *Gui) pollEvent() GocuiEvent {
 			switch button {
 			case tcell.ButtonPrimary:
 				mouseKey = MouseLeft
+				mouseMod = Modifier(lastMouseMod & (tcell.ModShift | tcell.ModCtrl))
 				dragState = MAYBE_DRAGGING
 				lastX = x
 				lastY = y
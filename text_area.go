@@ -4,6 +4,7 @@ import (
 	"regexp"
 	"slices"
 	"strings"
+	"unicode"
 
 	"github.com/rivo/uniseg"
 )
@@ -36,6 +37,41 @@ type TextArea struct {
 	clipboard     string
 	AutoWrap      bool
 	AutoWrapWidth int
+
+	// MaxLength is the maximum number of characters the content may have; 0
+	// means no limit.
+	MaxLength int
+	// If SingleLine is true, newlines are replaced with NewlineReplacement,
+	// or rejected if that is empty.
+	SingleLine         bool
+	NewlineReplacement string
+	// AllowedChars, if set, decides which characters may be typed, e.g.
+	// CharClass(unicode.Digit). Newlines are checked too, so multi-line
+	// text areas need to allow "\n".
+	AllowedChars func(ch string) bool
+	// InputMask restricts the content to a pattern like "####-##-##": '#'
+	// stands for a digit, 'A' for a letter and '*' for any character; all
+	// other characters of the mask are literals, which are inserted
+	// automatically. With a mask, characters can only be typed at the end.
+	InputMask string
+	// Validate, if set, is called with the content whenever it changes. The
+	// result is available via ValidationError.
+	Validate func(content string) error
+
+	validationError error
+}
+
+// CharClass returns a filter for TextArea.AllowedChars that accepts the
+// characters in any of the given unicode tables.
+func CharClass(tables ...*unicode.RangeTable) func(ch string) bool {
+	return func(ch string) bool {
+		for _, r := range ch {
+			if !unicode.IsOneOf(tables, r) {
+				return false
+			}
+		}
+		return true
+	}
 }
 
 func stringToTextAreaCells(str string) []TextAreaCell {
@@ -74,6 +110,10 @@ func contentToCells(content string, autoWrapWidth int) ([]TextAreaCell, []int) {
 	var trailerMatcher trailerMatcher
 
 	cells := stringToTextAreaCells(content)
+	var breakAfter []bool
+	if autoWrapWidth > 0 {
+		breakAfter = textAreaBreakOpportunities(cells, autoWrapWidth)
+	}
 	y := 0
 
 	appendCellsSinceLineStart := func(to int) {
@@ -100,7 +140,7 @@ func contentToCells(content string, autoWrapWidth int) ([]TextAreaCell, []int) {
 			currentLineWidth += c.width
 			if c.char == " " && !footNoteMatcher.isFootNote() && !trailerMatcher.isTrailer() {
 				indexOfLastWhitespace = currentPos + 1
-			} else if autoWrapWidth > 0 && currentLineWidth > autoWrapWidth && indexOfLastWhitespace >= 0 {
+			} else if c.char != " " && autoWrapWidth > 0 && currentLineWidth > autoWrapWidth && indexOfLastWhitespace >= 0 {
 				wrapAt := indexOfLastWhitespace
 				appendCellsSinceLineStart(wrapAt)
 				contentIndex := cells[wrapAt].contentIndex
@@ -119,6 +159,12 @@ func contentToCells(content string, autoWrapWidth int) ([]TextAreaCell, []int) {
 
 			footNoteMatcher.addCharacter(c.char)
 			trailerMatcher.addCharacter(c.char)
+
+			// other break opportunities than spaces, e.g. between CJK
+			// characters or after a hyphen; trailers are never broken
+			if c.char != " " && breakAfter != nil && breakAfter[currentPos] && !trailerMatcher.couldBeTrailer() {
+				indexOfLastWhitespace = currentPos + 1
+			}
 		}
 	}
 
@@ -127,6 +173,35 @@ func contentToCells(content string, autoWrapWidth int) ([]TextAreaCell, []int) {
 	return result, softLineBreakIndices
 }
 
+// textAreaBreakOpportunities returns for each cell whether an auto-wrapped
+// line may be broken after it (see lineBreakOpportunities). Only lines that
+// are wider than autoWrapWidth are examined.
+func textAreaBreakOpportunities(cells []TextAreaCell, autoWrapWidth int) []bool {
+	result := make([]bool, len(cells))
+
+	lineStart, lineWidth := 0, 0
+	for i, c := range cells {
+		if c.char != "\n" {
+			lineWidth += c.width
+			if i < len(cells)-1 {
+				continue
+			}
+		}
+
+		if lineWidth > autoWrapWidth {
+			chrs := make([]string, 0, i+1-lineStart)
+			for _, c := range cells[lineStart : i+1] {
+				chrs = append(chrs, c.char)
+			}
+			breakAfter, _ := lineBreakOpportunities(chrs)
+			copy(result[lineStart:], breakAfter)
+		}
+		lineStart, lineWidth = i+1, 0
+	}
+
+	return result
+}
+
 var footNoteRe = regexp.MustCompile(`^\[\d+\]:\s*$`)
 
 type footNoteMatcher struct {
@@ -204,6 +279,17 @@ func (self *trailerMatcher) addCharacter(chr string) {
 	}
 
 	self.lineStr.WriteString(chr)
+
+	line := self.lineStr.String()
+	if !anyOf(supportedTrailers, func(trailer string) bool { return strings.HasPrefix(trailer, line) }) {
+		self.didFailToMatch = true
+	}
+}
+
+// couldBeTrailer returns true if the line is a trailer, or if it might still
+// turn out to be one.
+func (self *trailerMatcher) couldBeTrailer() bool {
+	return !self.didFailToMatch
 }
 
 func (self *trailerMatcher) isTrailer() bool {
@@ -248,26 +334,156 @@ func (self *TextArea) updateCells() {
 	}
 
 	self.cells, _ = contentToCells(self.content, width)
+	self.validate()
+}
+
+func (self *TextArea) validate() {
+	if self.Validate == nil {
+		self.validationError = nil
+		return
+	}
+
+	self.validationError = self.Validate(self.content)
+}
+
+// ValidationError returns the error that Validate returned for the current
+// content, or nil.
+func (self *TextArea) ValidationError() error {
+	return self.validationError
+}
+
+// constrainCharacter applies the input constraints to a character that is
+// about to be inserted, replacing the character under the cursor if
+// overwriting is true. It returns the string to insert instead, which may
+// contain literals of the input mask, and false if the character is rejected.
+// Lengths are counted in characters (grapheme clusters).
+func (self *TextArea) constrainCharacter(ch string, overwriting bool) (string, bool) {
+	if ch == "\n" && self.SingleLine {
+		if self.NewlineReplacement == "" {
+			return "", false
+		}
+		ch = self.NewlineReplacement
+	}
+
+	result := ch
+	if self.InputMask != "" {
+		var ok bool
+		if result, ok = self.applyInputMask(ch); !ok {
+			return "", false
+		}
+	} else if self.AllowedChars != nil && !self.AllowedChars(ch) {
+		return "", false
+	}
+
+	if self.MaxLength > 0 {
+		length := uniseg.GraphemeClusterCount(self.content) + uniseg.GraphemeClusterCount(result)
+		if overwriting {
+			length--
+		}
+		if length > self.MaxLength {
+			return "", false
+		}
+	}
+
+	return result, true
 }
 
-func (self *TextArea) typeCharacter(ch string) {
+// applyInputMask returns the given character, preceded by the literals of the
+// input mask that come before the next slot, or false if the character
+// doesn't fit into that slot. Typing a literal of the mask is always allowed.
+func (self *TextArea) applyInputMask(ch string) (string, bool) {
+	if !self.atEnd() {
+		return "", false
+	}
+
+	var mask []string
+	state := -1
+	for rest := self.InputMask; rest != ""; {
+		var slot string
+		slot, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		mask = append(mask, slot)
+	}
+
+	literals := ""
+	for position := uniseg.GraphemeClusterCount(self.content); position < len(mask); position++ {
+		slot := mask[position]
+		var matches bool
+		switch slot {
+		case "#":
+			matches = CharClass(unicode.Digit)(ch)
+		case "A":
+			matches = CharClass(unicode.Letter)(ch)
+		case "*":
+			matches = ch != "\n"
+		default:
+			if ch == slot {
+				return literals + ch, true
+			}
+			literals += slot
+			continue
+		}
+
+		if !matches || (self.AllowedChars != nil && !self.AllowedChars(ch)) {
+			return "", false
+		}
+		return literals + ch, true
+	}
+
+	return "", false
+}
+
+// insertCharacter inserts a character at the cursor, replacing the character
+// under the cursor if overwriting is true. All insertions go through here, so
+// that they honour the input constraints. It returns false if the character
+// is rejected.
+func (self *TextArea) insertCharacter(ch string, overwriting bool) bool {
+	ch, ok := self.constrainCharacter(ch, overwriting)
+	if !ok {
+		return false
+	}
+
 	widthToDelete := 0
-	if self.overwrite && !self.atEnd() {
+	if overwriting {
 		s, _, _, _ := uniseg.FirstGraphemeClusterInString(self.content[self.cursor:], -1)
 		widthToDelete = len(s)
 	}
 
 	self.content = self.content[:self.cursor] + ch + self.content[self.cursor+widthToDelete:]
 	self.cursor += len(ch)
+	return true
+}
+
+// insertString inserts the characters of a string at the cursor, dropping
+// the ones that the input constraints reject. If overwrite is true, they
+// replace the characters under the cursor. It returns false if any character
+// was dropped.
+func (self *TextArea) insertString(str string, overwrite bool) bool {
+	insertedAll := true
+	state := -1
+	for str != "" {
+		var chr string
+		chr, str, _, state = uniseg.FirstGraphemeClusterInString(str, state)
+		if !self.insertCharacter(chr, overwrite && !self.atEnd()) {
+			insertedAll = false
+		}
+	}
+	return insertedAll
+}
+
+// canDeleteUpTo reports whether the content may be deleted up to the given
+// byte offset. With an input mask, characters can only be removed from the
+// end, as removing any others would shift the later ones out of their slots.
+func (self *TextArea) canDeleteUpTo(end int) bool {
+	return self.InputMask == "" || end == len(self.content)
 }
 
 func (self *TextArea) TypeCharacter(ch string) {
-	self.typeCharacter(ch)
+	self.insertCharacter(ch, self.overwrite && !self.atEnd())
 	self.updateCells()
 }
 
 func (self *TextArea) BackSpaceChar() {
-	if self.cursor == 0 {
+	if self.cursor == 0 || !self.canDeleteUpTo(self.cursor) {
 		return
 	}
 
@@ -288,6 +504,9 @@ func (self *TextArea) DeleteChar() {
 
 	s, _, _, _ := uniseg.FirstGraphemeClusterInString(self.content[self.cursor:], -1)
 	widthToDelete := len(s)
+	if !self.canDeleteUpTo(self.cursor + widthToDelete) {
+		return
+	}
 	self.content = self.content[:self.cursor] + self.content[self.cursor+widthToDelete:]
 	self.updateCells()
 }
@@ -400,6 +619,9 @@ func (self *TextArea) atEnd() bool {
 func (self *TextArea) DeleteToStartOfLine() {
 	// copying vim's logic: if you're at the start of the line, you delete the newline
 	// character and go to the end of the previous line
+	if !self.canDeleteUpTo(self.cursor) {
+		return
+	}
 	if self.atLineStart() {
 		if self.cursor == 0 {
 			return
@@ -435,6 +657,9 @@ func (self *TextArea) DeleteToEndOfLine() {
 
 	// if we're at the end of the line, delete just the newline character
 	if self.atLineEnd() {
+		if !self.canDeleteUpTo(self.cursor + 1) {
+			return
+		}
 		self.content = self.content[:self.cursor] + self.content[self.cursor+1:]
 		self.updateCells()
 		return
@@ -444,11 +669,14 @@ func (self *TextArea) DeleteToEndOfLine() {
 	// break, so we'll end up deleting the next line. This seems like the
 	// only reasonable behavior in this case, as you can't delete just the soft
 	// line break.
+	lineEndIndex := self.closestNewlineOnRight()
+	if !self.canDeleteUpTo(lineEndIndex) {
+		return
+	}
 	if self.atSoftLineEnd() {
 		self.cursor++
 	}
 
-	lineEndIndex := self.closestNewlineOnRight()
 	self.clipboard = self.content[self.cursor:lineEndIndex]
 	self.content = self.content[:self.cursor] + self.content[lineEndIndex:]
 	self.updateCells()
@@ -546,7 +774,7 @@ func (self *TextArea) atSoftLineEnd() bool {
 
 func (self *TextArea) BackSpaceWord() {
 	newCursor := self.newCursorForMoveLeftWord()
-	if newCursor == self.cursor {
+	if newCursor == self.cursor || !self.canDeleteUpTo(self.cursor) {
 		return
 	}
 
@@ -638,19 +866,49 @@ func (self *TextArea) SetCursor2D(x int, y int) {
 	self.cursor = self.cellCursorToContentCursor(newCursor)
 }
 
+// GetCursor returns the position of the cursor as a byte offset into the
+// unwrapped content.
+func (self *TextArea) GetCursor() int {
+	return self.cursor
+}
+
+// currentTokenRange returns the byte offsets of the start and end of the
+// whitespace-delimited token that the cursor is in (or at the end of).
+func (self *TextArea) currentTokenRange() (int, int) {
+	start := self.cursor
+	for start > 0 && !strings.ContainsRune(WHITESPACES+"\n", rune(self.content[start-1])) {
+		start--
+	}
+	end := self.cursor
+	for end < len(self.content) && !strings.ContainsRune(WHITESPACES+"\n", rune(self.content[end])) {
+		end++
+	}
+	return start, end
+}
+
+// ReplaceCurrentToken replaces the whitespace-delimited token that the cursor
+// is in with the given string, and puts the cursor at the end of it. If the
+// input constraints reject any part of the replacement, the content is left
+// unchanged.
+func (self *TextArea) ReplaceCurrentToken(str string) {
+	oldContent, oldCursor := self.content, self.cursor
+	start, end := self.currentTokenRange()
+	self.content = self.content[:start] + self.content[end:]
+	self.cursor = start
+	if !self.insertString(str, false) {
+		self.content, self.cursor = oldContent, oldCursor
+	}
+	self.updateCells()
+}
+
 func (self *TextArea) Clear() {
 	self.content = ""
 	self.cells = nil
 	self.cursor = 0
+	self.validate()
 }
 
 func (self *TextArea) TypeString(str string) {
-	state := -1
-	for str != "" {
-		var chr string
-		chr, str, _, state = uniseg.FirstGraphemeClusterInString(str, state)
-		self.typeCharacter(chr)
-	}
-
+	self.insertString(str, self.overwrite)
 	self.updateCells()
 }
@@ -1,9 +1,12 @@
 package gocui
 
 import (
+	"errors"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
+	"unicode"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -958,6 +961,41 @@ func Test_AutoWrapContent(t *testing.T) {
 			expectedWrappedContent: "abc\nSigned-off-by:John \nDoe \n<john@doe.com>\n",
 			expectedSoftLineBreaks: []int{23, 27},
 		},
+		{
+			name:                   "wrap between CJK characters",
+			content:                "日本語のテキスト",
+			autoWrapWidth:          7,
+			expectedWrappedContent: "日本語\nのテキ\nスト",
+			expectedSoftLineBreaks: []int{9, 18},
+		},
+		{
+			name:                   "wrap after hyphen and em-dash",
+			content:                "abc-defgh—ijk",
+			autoWrapWidth:          7,
+			expectedWrappedContent: "abc-\ndefgh—\nijk",
+			expectedSoftLineBreaks: []int{4, 12},
+		},
+		{
+			name:                   "don't break inside URLs",
+			content:                "see https://long/link",
+			autoWrapWidth:          7,
+			expectedWrappedContent: "see \nhttps://long/link",
+			expectedSoftLineBreaks: []int{4},
+		},
+		{
+			name:                   "don't break at hyphens inside URLs",
+			content:                "go https://my-site",
+			autoWrapWidth:          12,
+			expectedWrappedContent: "go \nhttps://my-site",
+			expectedSoftLineBreaks: []int{3},
+		},
+		{
+			name:                   "don't break at hyphens of trailers",
+			content:                "Signed-off-by: John",
+			autoWrapWidth:          7,
+			expectedWrappedContent: "Signed-off-by: John",
+			expectedSoftLineBreaks: []int{},
+		},
 		{
 			name:                   "hard line breaks",
 			content:                "abc\ndef\n",
@@ -1041,3 +1079,184 @@ func BenchmarkTypeCharacter(b *testing.B) {
 		textArea.TypeCharacter("a")
 	}
 }
+
+func TestTextAreaConstraints(t *testing.T) {
+	tests := []struct {
+		name            string
+		setup           func(*TextArea)
+		input           string
+		expectedContent string
+	}{
+		{
+			name:            "max length",
+			setup:           func(ta *TextArea) { ta.MaxLength = 3 },
+			input:           "abcdé",
+			expectedContent: "abc",
+		},
+		{
+			name:            "max length counts graphemes",
+			setup:           func(ta *TextArea) { ta.MaxLength = 2 },
+			input:           "éé",
+			expectedContent: "éé",
+		},
+		{
+			name:            "single line rejects newlines",
+			setup:           func(ta *TextArea) { ta.SingleLine = true },
+			input:           "a\nb",
+			expectedContent: "ab",
+		},
+		{
+			name: "single line converts newlines",
+			setup: func(ta *TextArea) {
+				ta.SingleLine = true
+				ta.NewlineReplacement = " "
+			},
+			input:           "a\nb",
+			expectedContent: "a b",
+		},
+		{
+			name:            "allowed characters",
+			setup:           func(ta *TextArea) { ta.AllowedChars = CharClass(unicode.Digit, unicode.Space) },
+			input:           "1a 2b",
+			expectedContent: "1 2",
+		},
+		{
+			name:            "input mask inserts literals",
+			setup:           func(ta *TextArea) { ta.InputMask = "####-##-##" },
+			input:           "20240131",
+			expectedContent: "2024-01-31",
+		},
+		{
+			name:            "input mask accepts typed literals",
+			setup:           func(ta *TextArea) { ta.InputMask = "####-##-##" },
+			input:           "2024-01-31x9",
+			expectedContent: "2024-01-31",
+		},
+		{
+			name:            "input mask rejects mismatching characters",
+			setup:           func(ta *TextArea) { ta.InputMask = "AA-##" },
+			input:           "a1b2c34",
+			expectedContent: "ab-23",
+		},
+		{
+			name:            "allowed characters apply to newlines",
+			setup:           func(ta *TextArea) { ta.AllowedChars = CharClass(unicode.Digit) },
+			input:           "1\n2",
+			expectedContent: "12",
+		},
+		{
+			name: "allowed characters apply to newline replacements",
+			setup: func(ta *TextArea) {
+				ta.SingleLine = true
+				ta.NewlineReplacement = "_"
+				ta.AllowedChars = CharClass(unicode.Digit)
+			},
+			input:           "1\n2",
+			expectedContent: "12",
+		},
+		{
+			name: "max length includes newline replacements",
+			setup: func(ta *TextArea) {
+				ta.SingleLine = true
+				ta.NewlineReplacement = "  "
+				ta.MaxLength = 3
+			},
+			input:           "ab\nc",
+			expectedContent: "abc",
+		},
+		{
+			name: "max length includes literals of the input mask",
+			setup: func(ta *TextArea) {
+				ta.InputMask = "##-##"
+				ta.MaxLength = 3
+			},
+			input:           "1234",
+			expectedContent: "12",
+		},
+		{
+			name:            "input mask counts graphemes",
+			setup:           func(ta *TextArea) { ta.InputMask = "#e\u0301#" },
+			input:           "123",
+			expectedContent: "1e\u03012",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			textArea := &TextArea{}
+			test.setup(textArea)
+			textArea.TypeString(test.input)
+			assert.Equal(t, test.expectedContent, textArea.GetContent())
+		})
+	}
+}
+
+func TestReplaceCurrentTokenConstraints(t *testing.T) {
+	textArea := &TextArea{}
+	textArea.TypeString("ab cd")
+	textArea.MaxLength = 6
+	textArea.AllowedChars = CharClass(unicode.Letter, unicode.Space)
+	textArea.Validate = func(content string) error {
+		if strings.Contains(content, "z") {
+			return errors.New("no z")
+		}
+		return nil
+	}
+
+	// a rejected replacement keeps the token
+	textArea.ReplaceCurrentToken("xy1z2w")
+	assert.Equal(t, "ab cd", textArea.GetContent())
+	assert.Equal(t, 5, textArea.cursor)
+	assert.NoError(t, textArea.ValidationError())
+
+	textArea.ReplaceCurrentToken("xyz")
+	assert.Equal(t, "ab xyz", textArea.GetContent())
+	assert.Equal(t, 6, textArea.cursor)
+	assert.EqualError(t, textArea.ValidationError(), "no z")
+}
+
+func TestInputMaskDeletion(t *testing.T) {
+	textArea := &TextArea{InputMask: "####-##-##"}
+	textArea.TypeString("20240115")
+
+	// deleting in the middle would shift the later characters over the literals
+	textArea.SetCursor2D(4, 0)
+	textArea.BackSpaceChar()
+	textArea.DeleteChar()
+	textArea.BackSpaceWord()
+	textArea.DeleteToStartOfLine()
+	assert.Equal(t, "2024-01-15", textArea.GetContent())
+
+	textArea.SetCursor2D(9, 0)
+	textArea.DeleteChar()
+	assert.Equal(t, "2024-01-1", textArea.GetContent())
+	textArea.BackSpaceChar()
+	assert.Equal(t, "2024-01-", textArea.GetContent())
+
+	// truncating the content doesn't shift anything
+	textArea.SetCursor2D(4, 0)
+	textArea.DeleteToEndOfLine()
+	assert.Equal(t, "2024", textArea.GetContent())
+	textArea.TypeString("0120")
+	assert.Equal(t, "2024-01-20", textArea.GetContent())
+}
+
+func TestTextAreaValidation(t *testing.T) {
+	textArea := &TextArea{
+		Validate: func(content string) error {
+			if len(content) < 3 {
+				return errors.New("too short")
+			}
+			return nil
+		},
+	}
+
+	textArea.TypeString("ab")
+	assert.EqualError(t, textArea.ValidationError(), "too short")
+	textArea.TypeCharacter("c")
+	assert.NoError(t, textArea.ValidationError())
+	textArea.BackSpaceChar()
+	assert.EqualError(t, textArea.ValidationError(), "too short")
+	textArea.Clear()
+	assert.EqualError(t, textArea.ValidationError(), "too short")
+}
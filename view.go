@@ -5,8 +5,8 @@
 package gocui
 
 import (
-	"fmt"
 	"io"
+	"slices"
 	"strings"
 	"sync"
 	"unicode"
@@ -16,7 +16,8 @@ import (
 	"github.com/rivo/uniseg"
 )
 
-// Constants for overlapping edges
+// Constants for the edges of a view, used for overlapping edges and for the
+// sides of its frame
 const (
 	TOP    = 1 // view is overlapping at top edge
 	BOTTOM = 2 // view is overlapping at bottom edge
@@ -44,6 +45,18 @@ type View struct {
 	// a user starts a range select and then moves the cursor up.
 	rangeSelectStartY int
 
+	// The indices of lines that have been selected individually (e.g. with
+	// ctrl+click), in addition to the range selection above. Like
+	// rangeSelectStartY, these are relative to the first line of the view's
+	// content. Indices past the end of the content are ignored, so the selection
+	// survives the view's content being rewritten.
+	selectedLineIdxs map[int]struct{}
+
+	// The line from which ExtendLineSelection extends the selection. This is the
+	// line that was last toggled, or -1 if no line has been toggled yet, in which
+	// case the selected line is used.
+	selectionAnchorY int
+
 	// readBuffer is used for storing unread bytes
 	readBuffer []byte
 
@@ -57,6 +70,18 @@ type View struct {
 	// the location of the hyperlink that the mouse is currently hovering over; nil if none
 	hoveredHyperlink *SearchPosition
 
+	// the hyperlink that has the keyboard focus; nil if none
+	focusedHyperlink *hyperlinkRange
+
+	// the indices of the lines that have been written to since we last looked
+	// for hyperlinks in them
+	linksDirtyLines map[int]struct{}
+
+	// the links that the link detectors found, by line index, and the
+	// functions that open the links of detectors that have their own
+	detectedLinks map[int][]detectedLink
+	linkOpeners   map[string]func(string, string) error
+
 	// internal representation of the view's buffer. We will keep viewLines around
 	// from a previous render until we explicitly set them to nil, allowing us to
 	// render the same content twice without flicker. Wherever we want to render
@@ -79,6 +104,48 @@ type View struct {
 	// Visible specifies whether the view is visible.
 	Visible bool
 
+	// If Focusable is true, the view takes part in keyboard focus traversal
+	// (see Gui.FocusNext), unless an explicit focus order has been set.
+	Focusable bool
+
+	// OnFocus and OnBlur, if set, are called after the view has gained or
+	// lost the focus, respectively.
+	OnFocus func()
+	OnBlur  func()
+
+	// OnMouseEnter and OnMouseLeave, if set, are called when the mouse pointer
+	// enters or leaves the view. OnMouseHover is called whenever the pointer
+	// moves over the view's content, with the position relative to the content
+	// (i.e. origin + cursor).
+	OnMouseEnter func()
+	OnMouseLeave func()
+	OnMouseHover func(x, y int)
+
+	// Tooltip is shown in a small floating view when the mouse pointer rests on
+	// the view's content for Gui.TooltipDelay. If TooltipFunc is set, it is
+	// asked for the tooltip of the hovered position instead; an empty string
+	// means no tooltip.
+	Tooltip     string
+	TooltipFunc func(x, y int) string
+
+	// If CompletionProvider is set on an editable view, it is asked for
+	// completion candidates whenever the content changes. The candidates are
+	// shown in a dropdown below the cursor; Up/Down select a candidate, and
+	// Tab/Enter replace the token at the cursor with it.
+	CompletionProvider CompletionProvider
+
+	// If History is set on an editable view whose TextArea is SingleLine, Up
+	// and Down cycle through the entries of the history named HistoryName (or
+	// the view's name, if empty), and Ctrl+R searches them. Call AddToHistory
+	// when the prompt is submitted.
+	History      *InputHistory
+	HistoryName  string
+	historyState *historyState
+
+	// If Highlighter is set, RenderTextArea uses it to style the content of
+	// the TextArea.
+	Highlighter Highlighter
+
 	// BgColor and FgColor allow to configure the background and foreground
 	// colors of the View.
 	BgColor, FgColor Attribute
@@ -111,6 +178,10 @@ type View struct {
 	// instead of Sel{Bg,Fg}Colors for highlighting selected lines.
 	HighlightInactive bool
 
+	// If MultiSelect is true, ctrl+click toggles the selection of the clicked
+	// line and shift+click extends the selection up to the clicked line.
+	MultiSelect bool
+
 	// If Frame is true, a border will be drawn around the view.
 	Frame bool
 
@@ -130,13 +201,70 @@ type View struct {
 	// 11 runes which can be used with `gocui.Gui.SupportOverlaps` property.
 	//  []rune{'─', '│', '┌', '┐', '└', '┘', '├', '┤', '┬', '┴', '┼'}
 	//  []rune{'═','║','╔','╗','╚','╝','╠','╣','╦','╩','╬'}
+	//
+	// FrameStyle.Runes returns the runes of a number of predefined styles.
 	FrameRunes []rune
 
+	// FrameSides selects the sides on which the frame is drawn, as a
+	// combination of TOP, BOTTOM, LEFT and RIGHT, e.g. only TOP for a
+	// horizontal rule above the content. 0 means all sides. The content
+	// extends to the sides without a frame.
+	FrameSides byte
+
+	// PaddingLeft, PaddingRight, PaddingTop and PaddingBottom are the number
+	// of blank cells between the frame and the content on each side. The
+	// gutter is part of the content.
+	PaddingLeft, PaddingRight, PaddingTop, PaddingBottom int
+
+	// If Shadow is true, a shadow is drawn on the cells to the right of and
+	// below the view, e.g. for popups. See Gui.ShadowColor.
+	Shadow bool
+
+	// Backdrop selects how the views beneath this one are shown while it is
+	// visible, e.g. dimmed for a modal popup.
+	Backdrop BackdropMode
+
 	// If Wrap is true, the content that is written to this View is
 	// automatically wrapped when it is longer than its width. If true the
 	// view's x-origin will be ignored.
 	Wrap bool
 
+	// Alignment selects whether lines that are narrower than the view are
+	// drawn at its left edge, centred, or at its right edge.
+	Alignment Alignment
+
+	// LineAlignment, if set, overrides Alignment for individual lines. It is
+	// called with the index of a line of the content; the lines that a wrapped
+	// line is split into are aligned separately.
+	LineAlignment func(lineIdx int) Alignment
+
+	// If TruncateWithEllipsis is true, lines that are cut off at the right
+	// edge of the view end with an ellipsis. This has no effect if Wrap is
+	// true. The content of the view is not changed.
+	TruncateWithEllipsis bool
+
+	// If BidiReordering is true, lines that contain right-to-left text (e.g.
+	// Hebrew or Arabic) are shown in visual order, according to the Unicode
+	// bidirectional algorithm. Lines whose first strong character is
+	// right-to-left are laid out right-to-left. Positions in the view's API,
+	// e.g. the cursor and mouse coordinates, still refer to the content.
+	BidiReordering bool
+
+	// LineNumbers selects whether line numbers are shown in a gutter to the
+	// left of the content. Wrapped lines are numbered once.
+	LineNumbers LineNumberMode
+
+	// Signs, if set, returns the sign to show in the gutter for the line with
+	// the given index (e.g. "+" or "-" for a diff), and its color; ColorDefault
+	// means GutterFgColor. The sign column is SignColumnWidth cells wide, or
+	// 1 if that is 0.
+	Signs           func(lineIdx int) (string, Attribute)
+	SignColumnWidth int
+
+	// GutterFgColor and GutterBgColor are the colors of the gutter; if they
+	// are ColorDefault, the view's colors are used.
+	GutterFgColor, GutterBgColor Attribute
+
 	// If Autoscroll is true, the View will automatically scroll down when the
 	// text overflows. If true the view's y-origin will be ignored.
 	Autoscroll bool
@@ -151,12 +279,23 @@ type View struct {
 	Tabs     []string
 	TabIndex int
 
+	// TabBadges allows to show a badge (e.g. a count) next to each tab. The
+	// badge at a given index belongs to the tab with the same index.
+	TabBadges []string
+
 	// TitleColor allow to configure the color of title and subtitle for the view.
 	TitleColor Attribute
 
 	// If Frame is true, Subtitle allows to configure a subtitle for the view.
 	Subtitle string
 
+	// If ShowValidationError is true, a validation error of the TextArea (see
+	// TextArea.Validate) is shown in place of the subtitle. If
+	// ValidationErrorFrameColor is set, the frame is drawn in that color while
+	// there is a validation error.
+	ShowValidationError       bool
+	ValidationErrorFrameColor Attribute
+
 	// If Mask is true, the View will display the mask instead of the real
 	// content
 	Mask string
@@ -167,7 +306,8 @@ type View struct {
 	// If HasLoader is true, the message will be appended with a spinning loader animation
 	HasLoader bool
 
-	// ParentView is the view which catches events bubbled up from the given view if there's no matching handler
+	// ParentView is the view which catches events bubbled up from the given view if there's no matching handler.
+	// Mouse events bubble up through the whole chain of parent views.
 	ParentView *View
 
 	searcher *searcher
@@ -181,6 +321,10 @@ type View struct {
 	// something like '1 of 20' for a list view
 	Footer string
 
+	// FrameSegments are styled pieces of text that are drawn on the top and
+	// bottom edges of the frame, e.g. status indicators or buttons.
+	FrameSegments []*FrameSegment
+
 	// if true, the user can scroll all the way past the last item until it appears at the top of the view
 	CanScrollPastBottom bool
 
@@ -188,12 +332,23 @@ type View struct {
 	// them as hyperlinks
 	AutoRenderHyperLinks bool
 
+	// LinkDetectors turn more kinds of text into hyperlinks, such as file
+	// locations or issue references. They run whether or not
+	// AutoRenderHyperLinks is set.
+	LinkDetectors []*LinkDetector
+
 	// if true, the view will underline hyperlinks only when the cursor is on
 	// them; otherwise, they will always be underlined
 	UnderlineHyperLinksOnlyOnHover bool
 
 	// number of spaces per \t character, defaults to 4
 	TabWidth int
+
+	// if true, text written to the view is parsed for style markup like
+	// [red::b]; see WriteMarkup for the syntax. The content of the text area
+	// of an editable view is never parsed for markup, so that what the user
+	// types is shown as typed.
+	Markup bool
 }
 
 type pos struct {
@@ -207,6 +362,7 @@ func (v *View) clearViewLines() {
 	v.tainted = true
 	v.viewLines = nil
 	v.clearHover()
+	v.focusedHyperlink = nil
 }
 
 type searcher struct {
@@ -401,6 +557,82 @@ func (v *View) CancelRangeSelect() {
 	v.rangeSelectStartY = -1
 }
 
+// SetLineSelected adds the line at the given index to the selection, or
+// removes it, independently of the range selection.
+func (v *View) SetLineSelected(idx int, selected bool) {
+	if idx < 0 {
+		return
+	}
+
+	if selected {
+		if v.selectedLineIdxs == nil {
+			v.selectedLineIdxs = map[int]struct{}{}
+		}
+		v.selectedLineIdxs[idx] = struct{}{}
+	} else {
+		delete(v.selectedLineIdxs, idx)
+	}
+	v.selectionAnchorY = idx
+}
+
+// ToggleLineSelected toggles whether the line at the given index is selected.
+// This is what you would bind to e.g. the space key in a list view.
+func (v *View) ToggleLineSelected(idx int) {
+	v.SetLineSelected(idx, !v.isLineToggled(idx))
+}
+
+// ExtendLineSelection selects all lines between the line that was last
+// toggled (or the selected line, if none was toggled) and the given index.
+func (v *View) ExtendLineSelection(idx int) {
+	anchor := v.selectionAnchorY
+	if anchor == -1 {
+		anchor = v.viewLineToLineIdx(v.SelectedLineIdx())
+	}
+
+	for i := min(anchor, idx); i <= max(anchor, idx); i++ {
+		v.SetLineSelected(i, true)
+	}
+	// keep the anchor where it was so that subsequent shift+clicks extend from
+	// the same line
+	v.selectionAnchorY = anchor
+}
+
+// ClearLineSelection deselects all individually selected lines. It doesn't
+// affect the range selection; use CancelRangeSelect for that.
+func (v *View) ClearLineSelection() {
+	v.selectedLineIdxs = nil
+	v.selectionAnchorY = -1
+}
+
+func (v *View) isLineToggled(idx int) bool {
+	if idx >= len(v.lines) {
+		return false
+	}
+
+	_, ok := v.selectedLineIdxs[idx]
+	return ok
+}
+
+// viewLineToLineIdx returns the index of the line of the view's content that
+// the view line with the given index belongs to. This differs from the view
+// line index if the view wraps lines.
+func (v *View) viewLineToLineIdx(y int) int {
+	if y < 0 || y >= len(v.viewLines) {
+		return y
+	}
+	return v.viewLines[y].linesY
+}
+
+// isViewLineToggled returns true if the view line at the given position on
+// the screen belongs to a line that was selected individually.
+func (v *View) isViewLineToggled(y int) bool {
+	if len(v.selectedLineIdxs) == 0 || y+v.oy >= len(v.viewLines) {
+		return false
+	}
+
+	return v.isLineToggled(v.viewLineToLineIdx(y + v.oy))
+}
+
 func calculateNewOrigin(selectedLine int, oldOrigin int, lineCount int, viewHeight int) int {
 	if viewHeight >= lineCount {
 		return 0
@@ -492,6 +724,7 @@ func NewView(name string, x0, y0, x1, y1 int, mode OutputMode) *View {
 		searcher:          &searcher{},
 		TextArea:          &TextArea{},
 		rangeSelectStartY: -1,
+		selectionAnchorY:  -1,
 		TabWidth:          4,
 	}
 
@@ -519,6 +752,15 @@ func (v *View) InnerSize() (x, y int) {
 	return v.InnerWidth(), v.InnerHeight()
 }
 
+// subtitle returns the subtitle of the view, or the validation error of its
+// text area if it should be shown instead.
+func (v *View) subtitle() string {
+	if err := v.TextArea.ValidationError(); err != nil && v.Editable && v.ShowValidationError {
+		return err.Error()
+	}
+	return v.Subtitle
+}
+
 func (v *View) Width() int {
 	return v.x1 - v.x0 + 1
 }
@@ -531,8 +773,10 @@ func (v *View) Height() int {
 // because if it has a frame, we need to subtract that, but if it doesn't, the
 // view is made 1 larger on all sides. I'd like to clean this up at some point,
 // but for now we live with this weirdness.
+//
+// The gutter (see GutterWidth) is not part of the writeable area.
 func (v *View) InnerWidth() int {
-	innerWidth := v.Width() - 2
+	innerWidth := v.Width() - v.contentInset(LEFT) - v.contentInset(RIGHT) - v.GutterWidth()
 	if innerWidth < 0 {
 		return 0
 	}
@@ -541,7 +785,7 @@ func (v *View) InnerWidth() int {
 }
 
 func (v *View) InnerHeight() int {
-	innerHeight := v.Height() - 2
+	innerHeight := v.Height() - v.contentInset(TOP) - v.contentInset(BOTTOM)
 	if innerHeight < 0 {
 		return 0
 	}
@@ -556,8 +800,9 @@ func (v *View) Name() string {
 
 // setCharacter sets a character (grapheme cluster) at the given point relative to the view. It applies
 // the specified colors, taking into account if the cell must be highlighted. Also, it checks if the
-// position is valid.
-func (v *View) setCharacter(x, y int, ch string, fgColor, bgColor Attribute) {
+// position is valid. contentX is the character's x position in the content of the view line, which is
+// x plus the x-origin unless the line is reordered for bidirectional text.
+func (v *View) setCharacter(x, y int, contentX int, ch string, fgColor, bgColor Attribute) {
 	maxX, maxY := v.Size()
 	if x < 0 || x >= maxX || y < 0 || y >= maxY {
 		return
@@ -576,7 +821,7 @@ func (v *View) setCharacter(x, y int, ch string, fgColor, bgColor Attribute) {
 			rangeSelectEnd = max(relativeRangeSelectStart, v.cy)
 		}
 
-		if y >= rangeSelectStart && y <= rangeSelectEnd {
+		if (y >= rangeSelectStart && y <= rangeSelectEnd) || v.isViewLineToggled(y) {
 			// this ensures we use the bright variant of a colour upon highlight
 			fgColorComponent := fgColor & ^AttrAll
 			if fgColorComponent >= AttrIsValidColor && fgColorComponent < AttrIsValidColor+8 {
@@ -591,7 +836,7 @@ func (v *View) setCharacter(x, y int, ch string, fgColor, bgColor Attribute) {
 		}
 	}
 
-	if matched, selected := v.isPatternMatchedRune(x, y); matched {
+	if matched, selected := v.isPatternMatchedRune(contentX, y); matched {
 		fgColor = ColorBlack
 		if selected {
 			bgColor = ColorCyan
@@ -600,16 +845,20 @@ func (v *View) setCharacter(x, y int, ch string, fgColor, bgColor Attribute) {
 		}
 	}
 
-	if v.isHoveredHyperlink(x, y) {
+	if v.isHoveredHyperlink(contentX, y) {
 		fgColor |= AttrUnderline
 	}
 
+	if v.isFocusedHyperlink(contentX, y) {
+		fgColor |= AttrReverse | AttrUnderline
+	}
+
 	// Don't display empty characters
 	if ch == "" {
 		ch = " "
 	}
 
-	tcellSetCell(v.x0+x+1, v.y0+y+1, ch, fgColor, bgColor, v.outMode)
+	tcellSetCell(v.contentX0()+x, v.contentY0()+y, ch, fgColor, bgColor, v.outMode)
 }
 
 // SetCursor sets the cursor position of the view at the given point,
@@ -779,6 +1028,10 @@ func (v *View) writeCells(cells []cell) {
 	}
 	v.lines[v.wy] = line[:newLen]
 	v.wx += len(cells)
+	if v.linksDirtyLines == nil {
+		v.linksDirtyLines = map[int]struct{}{}
+	}
+	v.linksDirtyLines[v.wy] = struct{}{}
 }
 
 // Write appends a byte slice into the view's internal buffer. Because
@@ -795,8 +1048,13 @@ func (v *View) Write(p []byte) (n int, err error) {
 }
 
 func (v *View) write(p []byte) {
+	v.writeWithMarkup(p, v.Markup)
+}
+
+func (v *View) writeWithMarkup(p []byte, markup bool) {
 	v.tainted = true
 	v.clearHover()
+	v.focusedHyperlink = nil
 
 	// Fill with empty cells, if writing outside current view buffer
 	v.makeWriteable(v.wx, v.wy)
@@ -832,6 +1090,29 @@ func (v *View) write(p []byte) {
 		until--
 	}
 
+	writeChr := func(chr []byte, width int) {
+		truncateLine, cells := v.parseInput(chr, width, v.wx, v.wy)
+		if cells == nil {
+			return
+		}
+		v.writeCells(cells)
+		if truncateLine {
+			v.lines[v.wy] = v.lines[v.wy][:v.wx]
+		}
+	}
+
+	// text that turns out not to be markup is written as it is
+	var mp markupParser
+	writeLiteral := func(s string) {
+		state := -1
+		var chr string
+		var width int
+		for s != "" {
+			chr, s, width, state = uniseg.FirstGraphemeClusterInString(s, state)
+			writeChr([]byte(chr), width)
+		}
+	}
+
 	state := -1
 	var chr []byte
 	var width int
@@ -840,6 +1121,15 @@ func (v *View) write(p []byte) {
 	for len(remaining) > 0 {
 		chr, remaining, width, state = uniseg.FirstGraphemeCluster(remaining, state)
 
+		// markup is not parsed inside of escape sequences
+		if markup && v.ei.state == stateNone {
+			literal, isMarkup := mp.parseOne(chr, v.ei)
+			writeLiteral(literal)
+			if isMarkup {
+				continue
+			}
+		}
+
 		switch {
 		case characterEquals(chr, '\n') || isCRLF(chr):
 			finishLine()
@@ -848,16 +1138,10 @@ func (v *View) write(p []byte) {
 			finishLine()
 			v.wx = 0
 		default:
-			truncateLine, cells := v.parseInput(chr, width, v.wx, v.wy)
-			if cells == nil {
-				continue
-			}
-			v.writeCells(cells)
-			if truncateLine {
-				v.lines[v.wy] = v.lines[v.wy][:v.wx]
-			}
+			writeChr(chr, width)
 		}
 	}
+	writeLiteral(mp.flush())
 
 	if v.pendingNewline {
 		finishLine()
@@ -909,10 +1193,20 @@ var lineEndCharacters map[string]bool = map[string]bool{
 }
 
 func (v *View) autoRenderHyperlinksInCurrentLine() {
-	if !v.AutoRenderHyperLinks {
+	if _, ok := v.linksDirtyLines[v.wy]; !ok {
 		return
 	}
+	delete(v.linksDirtyLines, v.wy)
+
+	if v.AutoRenderHyperLinks {
+		v.autoRenderURLsInCurrentLine()
+	}
+	if len(v.LinkDetectors) > 0 {
+		v.detectLinksInCurrentLine()
+	}
+}
 
+func (v *View) autoRenderURLsInCurrentLine() {
 	line := v.lines[v.wy]
 	start := 0
 	for {
@@ -1037,6 +1331,9 @@ func (v *View) Read(p []byte) (n int, err error) {
 func (v *View) clear() {
 	v.rewind()
 	v.lines = nil
+	v.linksDirtyLines = nil
+	v.detectedLinks = nil
+	v.linkOpeners = nil
 	v.clearViewLines()
 }
 
@@ -1087,6 +1384,8 @@ func (v *View) Reset() {
 
 	v.rewind()
 	v.lines = nil
+	v.linksDirtyLines = nil
+	v.detectedLinks = nil
 }
 
 // This is for when we've done a restart for the sake of avoiding a flicker and
@@ -1224,6 +1523,7 @@ func (v *View) draw() {
 	}
 
 	v.clearRunes()
+	v.clearPadding()
 
 	maxX, maxY := v.InnerSize()
 
@@ -1241,15 +1541,17 @@ func (v *View) draw() {
 		v.oy = visibleViewLinesHeight - maxY
 	}
 
-	if len(v.viewLines) == 0 {
-		return
-	}
-
 	start := v.oy
 	if start > len(v.viewLines)-1 {
 		start = len(v.viewLines) - 1
 	}
 
+	v.drawGutter(start, maxY)
+
+	if len(v.viewLines) == 0 {
+		return
+	}
+
 	emptyCell := cell{chr: " ", width: 1, fgColor: ColorDefault, bgColor: ColorDefault}
 	var prevFgColor Attribute
 
@@ -1258,12 +1560,31 @@ func (v *View) draw() {
 			break
 		}
 
+		// with bidi reordering, we draw the cells in visual order, and
+		// contentXs holds their positions in the line's content
+		line := vline.line
+		var contentXs []int
+		if cells, xs := v.bidiLine(start + y); cells != nil {
+			line, contentXs = cells, xs
+		}
+
+		if v.TruncateWithEllipsis && !v.Wrap {
+			line, contentXs = truncateLine(line, contentXs, v.ox+maxX)
+		}
+
 		// x tracks the current x position in the view, and cellIdx tracks the
 		// index of the cell. If we print a double-sized rune, we increment cellIdx
 		// by one but x by two.
-		x := -v.ox
+		offset := v.alignmentOffset(start + y)
+		x := offset - v.ox
 		cellIdx := 0
 
+		// the columns left of an aligned line are empty, but they still need to
+		// be drawn for the line's highlighting
+		for emptyX := 0; emptyX < min(x, maxX); emptyX++ {
+			v.setCharacter(emptyX, y, emptyX-x, emptyCell.chr, v.FgColor, v.BgColor)
+		}
+
 		var c cell
 		for {
 			if x >= maxX {
@@ -1271,8 +1592,8 @@ func (v *View) draw() {
 			}
 
 			if x < 0 {
-				if cellIdx < len(vline.line) {
-					x += uniseg.StringWidth(vline.line[cellIdx].chr)
+				if cellIdx < len(line) {
+					x += uniseg.StringWidth(line[cellIdx].chr)
 					cellIdx++
 					continue
 				} else {
@@ -1283,11 +1604,15 @@ func (v *View) draw() {
 			}
 
 			// if we're out of cells to write, we'll just print empty cells.
-			if cellIdx > len(vline.line)-1 {
+			contentX := x + v.ox - offset
+			if cellIdx > len(line)-1 {
 				c = emptyCell
 				c.fgColor = prevFgColor
 			} else {
-				c = vline.line[cellIdx]
+				c = line[cellIdx]
+				if contentXs != nil {
+					contentX = contentXs[cellIdx]
+				}
 				// capturing previous foreground colour so that if we're using the reverse
 				// attribute we honour the final character's colour and don't awkwardly switch
 				// to a new background colour for the remainder of the line
@@ -1306,7 +1631,7 @@ func (v *View) draw() {
 				fgColor |= AttrUnderline
 			}
 
-			v.setCharacter(x, y, c.chr, fgColor, bgColor)
+			v.setCharacter(x, y, contentX, c.chr, fgColor, bgColor)
 
 			x += c.width
 			cellIdx++
@@ -1358,22 +1683,20 @@ func (v *View) viewLineLengthIgnoringTrailingBlankLines() int {
 	return 0
 }
 
-func (v *View) isPatternMatchedRune(x, y int) (bool, bool) {
+func (v *View) isPatternMatchedRune(contentX, y int) (bool, bool) {
 	for i, pos := range v.searcher.searchPositions {
 		adjustedY := y + v.oy
-		adjustedX := x + v.ox
-		if adjustedY == pos.Y && adjustedX >= pos.XStart && adjustedX < pos.XEnd {
+		if adjustedY == pos.Y && contentX >= pos.XStart && contentX < pos.XEnd {
 			return true, i == v.searcher.currentSearchIndex
 		}
 	}
 	return false, false
 }
 
-func (v *View) isHoveredHyperlink(x, y int) bool {
+func (v *View) isHoveredHyperlink(contentX, y int) bool {
 	if v.UnderlineHyperLinksOnlyOnHover && v.hoveredHyperlink != nil {
 		adjustedY := y + v.oy
-		adjustedX := x + v.ox
-		return adjustedY == v.hoveredHyperlink.Y && adjustedX >= v.hoveredHyperlink.XStart && adjustedX < v.hoveredHyperlink.XEnd
+		return adjustedY == v.hoveredHyperlink.Y && contentX >= v.hoveredHyperlink.XStart && contentX < v.hoveredHyperlink.XEnd
 	}
 	return false
 }
@@ -1408,9 +1731,10 @@ func (v *View) realPosition(vx, vy int) (x, y int, ok bool) {
 // clearRunes erases all the cells in the view.
 func (v *View) clearRunes() {
 	maxX, maxY := v.InnerSize()
+	x0, y0 := v.contentX0(), v.contentY0()
 	for x := range maxX {
 		for y := range maxY {
-			tcellSetCell(v.x0+x+1, v.y0+y+1, " ", v.FgColor, v.BgColor, v.outMode)
+			tcellSetCell(x0+x, y0+y, " ", v.FgColor, v.BgColor, v.outMode)
 		}
 	}
 }
@@ -1561,6 +1885,7 @@ func lineWrap(line []cell, columns int) [][]cell {
 	var offset int
 	lastWhitespaceIndex := -1
 	lines := make([][]cell, 0, 1)
+	breakAfter, inURL := viewLineBreakOpportunities(line, columns)
 	for i := range line {
 		currChr := line[i].chr
 		rw := uniseg.StringWidth(currChr)
@@ -1578,19 +1903,19 @@ func lineWrap(line []cell, columns int) [][]cell {
 				lines = append(lines, line[offset:i])
 				offset = i + 1
 				n = 0
-			} else if currChr == "-" {
+			} else if currChr == "-" && !inURL[i] {
 				// if the last character is hyphen and the width of line is equal to the columns
 				lines = append(lines, line[offset:i])
 				offset = i
 				n = rw
 			} else if lastWhitespaceIndex != -1 {
-				// if there is a space in the line and the line is not breaking at a space/hyphen
-				if line[lastWhitespaceIndex].chr == "-" {
-					// if break occurs at hyphen, we'll retain the hyphen
-					lines = append(lines, line[offset:lastWhitespaceIndex+1])
-				} else {
+				// if there is a break opportunity in the line and the line is not breaking at a space/hyphen
+				if line[lastWhitespaceIndex].chr == " " {
 					// if break occurs at space, we'll omit the space
 					lines = append(lines, line[offset:lastWhitespaceIndex])
+				} else {
+					// otherwise (e.g. at a hyphen, or between two CJK characters), we'll retain the character
+					lines = append(lines, line[offset:lastWhitespaceIndex+1])
 				}
 				// Either way, continue *after* the break
 				offset = lastWhitespaceIndex + 1
@@ -1605,7 +1930,7 @@ func lineWrap(line []cell, columns int) [][]cell {
 				n = rw
 			}
 			lastWhitespaceIndex = -1
-		} else if line[i].chr == " " || line[i].chr == "-" {
+		} else if breakAfter != nil && (line[i].chr == " " || line[i].chr == "-" && !inURL[i] || breakAfter[i]) {
 			lastWhitespaceIndex = i
 		}
 	}
@@ -1614,6 +1939,25 @@ func lineWrap(line []cell, columns int) [][]cell {
 	return lines
 }
 
+// viewLineBreakOpportunities returns the break opportunities of a view line
+// (see lineBreakOpportunities), or nil if the line fits into the given number
+// of columns anyway.
+func viewLineBreakOpportunities(line []cell, columns int) ([]bool, []bool) {
+	width := 0
+	for _, c := range line {
+		width += c.width
+	}
+	if width <= columns {
+		return nil, nil
+	}
+
+	chrs := make([]string, len(line))
+	for i, c := range line {
+		chrs[i] = c.chr
+	}
+	return lineBreakOpportunities(chrs)
+}
+
 func linesToString(lines [][]cell) string {
 	str := make([]string, len(lines))
 	for i := range lines {
@@ -1630,33 +1974,6 @@ func linesToString(lines [][]cell) string {
 	return strings.Join(str, "\n")
 }
 
-// GetClickedTabIndex tells us which tab was clicked
-func (v *View) GetClickedTabIndex(x int) int {
-	if len(v.Tabs) <= 1 {
-		return 0
-	}
-
-	charX := len(v.TitlePrefix) + 1
-	if v.TitlePrefix != "" {
-		charX += 1
-	}
-	if x <= charX {
-		return -1
-	}
-	for i, tab := range v.Tabs {
-		charX += uniseg.StringWidth(tab)
-		if x <= charX {
-			return i
-		}
-		charX += uniseg.StringWidth(" - ")
-		if x <= charX {
-			return -1
-		}
-	}
-
-	return -1
-}
-
 func (v *View) SelectedLineIdx() int {
 	_, seletedLineIdx := v.SelectedPoint()
 	return seletedLineIdx
@@ -1724,9 +2041,39 @@ func (v *View) SelectedLineRange() (int, int) {
 	}
 }
 
+// SelectedLineIndices returns the sorted indices of all selected lines. This
+// is the union of the individually selected lines and the range selection; if
+// no line was selected individually, it is just the range returned by
+// SelectedLineRange.
+func (v *View) SelectedLineIndices() []int {
+	startIdx, endIdx := v.SelectedLineRange()
+
+	var result []int
+	for idx := range v.selectedLineIdxs {
+		if idx < len(v.lines) {
+			result = append(result, idx)
+		}
+	}
+
+	if len(result) == 0 || v.rangeSelectStartY != -1 {
+		for i := startIdx; i <= endIdx; i++ {
+			result = append(result, i)
+		}
+	}
+
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
 func (v *View) RenderTextArea() {
 	v.Clear()
-	fmt.Fprint(v, v.TextArea.GetContent())
+	if _, _, hasSelection := v.textAreaSelection(); v.Highlighter != nil || hasSelection {
+		v.writeHighlightedTextArea()
+	} else {
+		v.writeMutex.Lock()
+		v.writeWithMarkup([]byte(v.TextArea.GetContent()), false)
+		v.writeMutex.Unlock()
+	}
 	cursorX, cursorY := v.TextArea.GetCursorXY()
 	prevOriginX, prevOriginY := v.Origin()
 	width, height := v.InnerWidth(), v.InnerHeight()
@@ -1984,11 +2331,11 @@ func (v *View) onMouseMove(x int, y int) {
 	}
 
 	// newCx and newCy are relative to the view port, i.e. to the visible area of the view
-	newCx := x - v.x0 - 1
-	newCy := y - v.y0 - 1
+	newCx := x - v.contentX0()
+	newCy := y - v.contentY0()
 	// newX and newY are relative to the view's content, independent of its scroll position
-	newX := newCx + v.ox
 	newY := newCy + v.oy
+	newX := v.visualToLogicalX(newCx+v.ox, newY)
 
 	if newY >= 0 && newY <= len(v.viewLines)-1 && newX >= 0 && newX <= len(v.viewLines[newY].line)-1 {
 		if v.lastHoverPosition == nil || v.lastHoverPosition.x != newX || v.lastHoverPosition.y != newY {
@@ -5,9 +5,11 @@
 package gocui
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/uniseg"
 	"github.com/stretchr/testify/assert"
 )
@@ -357,14 +359,62 @@ func TestLineWrap(t *testing.T) {
 			},
 		},
 		{
+			// like ideographs, emoji can be broken between
 			name:    "Multi-cell runes",
 			line:    "🐤🐤🐤 🐝🐝 🙉 🦊🦊🦊-🐬🐬 🦢🦢",
 			columns: 9,
 			expected: []string{
-				"🐤🐤🐤",
-				"🐝🐝 🙉",
-				"🦊🦊🦊-",
-				"🐬🐬 🦢🦢",
+				"🐤🐤🐤 🐝",
+				"🐝 🙉 🦊",
+				"🦊🦊-🐬🐬",
+				"🦢🦢",
+			},
+		},
+		{
+			name:    "CJK text",
+			line:    "日本語のテキストです",
+			columns: 7,
+			expected: []string{
+				"日本語",
+				"のテキ",
+				"ストで",
+				"す",
+			},
+		},
+		{
+			name:    "Break after em-dash",
+			line:    "something—else",
+			columns: 12,
+			expected: []string{
+				"something—",
+				"else",
+			},
+		},
+		{
+			name:    "Don't break inside URLs",
+			line:    "see https://example.com/path",
+			columns: 24,
+			expected: []string{
+				"see",
+				"https://example.com/path",
+			},
+		},
+		{
+			name:    "Don't break at hyphens inside URLs",
+			line:    "go to https://my-site.com",
+			columns: 20,
+			expected: []string{
+				"go to",
+				"https://my-site.com",
+			},
+		},
+		{
+			name:    "Break URL that's too long",
+			line:    "https://example.com/path",
+			columns: 12,
+			expected: []string{
+				"https://exam",
+				"ple.com/path",
 			},
 		},
 		{
@@ -413,3 +463,168 @@ func TestLineWrap(t *testing.T) {
 		})
 	}
 }
+
+func TestSelectedLineIndices(t *testing.T) {
+	tests := []struct {
+		name     string
+		actions  func(*View)
+		expected []int
+	}{
+		{
+			name:     "No selection",
+			actions:  func(v *View) { v.SetCursorY(2) },
+			expected: []int{2},
+		},
+		{
+			name: "Range selection",
+			actions: func(v *View) {
+				v.SetCursorY(1)
+				v.SetRangeSelectStart(3)
+			},
+			expected: []int{1, 2, 3},
+		},
+		{
+			name: "Toggled lines",
+			actions: func(v *View) {
+				v.ToggleLineSelected(4)
+				v.ToggleLineSelected(0)
+				v.ToggleLineSelected(2)
+				v.ToggleLineSelected(4)
+			},
+			expected: []int{0, 2},
+		},
+		{
+			name: "Toggled lines and range selection",
+			actions: func(v *View) {
+				v.ToggleLineSelected(5)
+				v.SetCursorY(1)
+				v.SetRangeSelectStart(2)
+			},
+			expected: []int{1, 2, 5},
+		},
+		{
+			name: "Extend selection from toggled line",
+			actions: func(v *View) {
+				v.ToggleLineSelected(1)
+				v.ExtendLineSelection(3)
+				v.ExtendLineSelection(0)
+			},
+			expected: []int{0, 1, 2, 3},
+		},
+		{
+			name: "Extend selection from cursor",
+			actions: func(v *View) {
+				v.SetCursorY(4)
+				v.ExtendLineSelection(2)
+			},
+			expected: []int{2, 3, 4},
+		},
+		{
+			name: "Lines past the end of the content are ignored",
+			actions: func(v *View) {
+				v.ToggleLineSelected(1)
+				v.ToggleLineSelected(7)
+				v.SetContent("a\nb\nc")
+			},
+			expected: []int{1},
+		},
+		{
+			name: "Selection survives rewinding",
+			actions: func(v *View) {
+				v.ToggleLineSelected(1)
+				v.ToggleLineSelected(3)
+				v.Rewind()
+				fmt.Fprint(v, "a\nb\nc\nd")
+				v.ToggleLineSelected(2)
+			},
+			expected: []int{1, 2, 3},
+		},
+		{
+			name: "Clear selection",
+			actions: func(v *View) {
+				v.ToggleLineSelected(1)
+				v.ToggleLineSelected(3)
+				v.ClearLineSelection()
+			},
+			expected: []int{0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := NewView("name", 0, 0, 10, 10, OutputNormal)
+			v.SetContent("0\n1\n2\n3\n4\n5\n6\n7\n")
+			test.actions(v)
+			assert.Equal(t, test.expected, v.SelectedLineIndices())
+		})
+	}
+}
+
+func TestToggledLinesAreHighlighted(t *testing.T) {
+	setupSimulationScreen(t, 10, 10)
+
+	v := NewView("name", 0, 0, 9, 9, OutputNormal)
+	v.Highlight = true
+	v.Wrap = true
+	v.SelBgColor = ColorBlue
+	v.SetContent("0\n1\n2 wrapped line\n3\n4")
+	v.ToggleLineSelected(2)
+	v.ToggleLineSelected(4)
+	v.draw()
+
+	bgColors := make([]tcell.Color, 7)
+	for y := range bgColors {
+		_, style, _ := Screen.Get(1, y+1)
+		_, bgColors[y], _ = style.Decompose()
+	}
+	assert.Equal(t, []tcell.Color{
+		tcell.ColorNavy,    // 0, the cursor line
+		tcell.ColorDefault, // 1
+		tcell.ColorNavy,    // 2
+		tcell.ColorNavy,    // 2, wrapped
+		tcell.ColorNavy,    // 2, wrapped
+		tcell.ColorDefault, // 3
+		tcell.ColorNavy,    // 4
+	}, bgColors)
+}
+
+func TestMultiSelectClicks(t *testing.T) {
+	g := &Gui{maxX: 40, maxY: 20}
+	v, _ := g.SetView("v", 0, 0, 9, 10, 0)
+	v.Wrap = true
+	v.MultiSelect = true
+	v.SetContent("0\n1 wrapped line\n2\n3")
+	v.refreshViewLinesIfNeeded()
+
+	var plainClicks int
+	assert.NoError(t, g.SetViewClickBinding(&ViewMouseBinding{
+		ViewName: "v",
+		Key:      MouseLeft,
+		Handler: func(opts ViewMouseBindingOpts) error {
+			plainClicks++
+			return nil
+		},
+	}))
+
+	// line 1 is wrapped into three view lines; click on the second one
+	assert.NoError(t, g.onKey(&GocuiEvent{Type: eventMouse, Key: MouseLeft, Mod: ModCtrl, MouseX: 1, MouseY: 3}))
+	assert.Equal(t, []int{1}, v.SelectedLineIndices())
+
+	// clicks with modifiers still go to the binding of plain clicks
+	assert.Equal(t, 1, plainClicks)
+
+	// the last view line is line 3
+	assert.NoError(t, g.onKey(&GocuiEvent{Type: eventMouse, Key: MouseLeft, Mod: ModShift, MouseX: 1, MouseY: 6}))
+	assert.Equal(t, []int{1, 2, 3}, v.SelectedLineIndices())
+	assert.Equal(t, 2, plainClicks)
+
+	// the same goes for keybindings of the view
+	keybindingClicks := 0
+	assert.NoError(t, g.SetKeybinding("v", MouseLeft, ModNone, func(*Gui, *View) error {
+		keybindingClicks++
+		return nil
+	}))
+	g.viewMouseBindings = nil
+	assert.NoError(t, g.onKey(&GocuiEvent{Type: eventMouse, Key: MouseLeft, Mod: ModCtrl, MouseX: 1, MouseY: 1}))
+	assert.Equal(t, 1, keybindingClicks)
+}
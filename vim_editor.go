@@ -0,0 +1,787 @@
+package gocui
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// VimMode is the mode of a VimEditor.
+type VimMode int
+
+const (
+	VimModeNormal VimMode = iota
+	VimModeInsert
+	// VimModeVisual selects characters, VimModeVisualLine whole lines.
+	VimModeVisual
+	VimModeVisualLine
+)
+
+func (m VimMode) String() string {
+	switch m {
+	case VimModeInsert:
+		return "INSERT"
+	case VimModeVisual:
+		return "VISUAL"
+	case VimModeVisualLine:
+		return "VISUAL LINE"
+	default:
+		return "NORMAL"
+	}
+}
+
+// VimEditor is a modal Editor with vim-style key handling. In insert mode it
+// behaves like SimpleEditor until Esc is pressed. Normal mode supports counts,
+// the motions h, j, k, l, w, b, e, 0, ^, $, gg and G, the operators d, c and
+// y combined with a motion, a text object (iw, aw, il, al) or themselves (dd,
+// cc, yy), and the commands x, X, D, C, s, S, Y, p, P, i, a, I, A, o, O, v
+// and V. In visual mode, motions extend the selection and d, x, c and y act
+// on it.
+//
+// Keys that the editor doesn't use are left to the view's keybindings, e.g.
+// Enter, or Esc in normal mode. The zero value is ready to use and starts in
+// normal mode; since the editor keeps the mode and any pending keys, each
+// view needs its own VimEditor.
+type VimEditor struct {
+	// OnModeChange, if set, is called whenever the mode changes, e.g. to show
+	// the mode in a status bar or to change the cursor shape with
+	// Screen.SetCursorStyle.
+	OnModeChange func(mode VimMode)
+
+	mode VimMode
+
+	// the pending command: a count, an operator with the count that was typed
+	// before it, and a prefix that needs another key ('g', or 'i' and 'a' for
+	// text objects)
+	count         int
+	operator      rune
+	operatorCount int
+	prefix        rune
+
+	// the other end of the selection in visual mode; -1 means the cursor
+	// position when the next key is handled
+	visualAnchor int
+
+	register         string
+	registerLinewise bool
+}
+
+// motionKind determines which text a motion covers when it's used with an
+// operator.
+type motionKind int
+
+const (
+	// the text from the cursor up to, but not including, the target
+	motionExclusive motionKind = iota
+	// the text from the cursor up to and including the target
+	motionInclusive
+	// the whole lines from the cursor's line to the target's line
+	motionLinewise
+)
+
+// Mode returns the current mode.
+func (e *VimEditor) Mode() VimMode {
+	return e.mode
+}
+
+// SetMode switches to the given mode and discards any pending command.
+func (e *VimEditor) SetMode(mode VimMode) {
+	e.resetPending()
+	if mode == VimModeVisual || mode == VimModeVisualLine {
+		e.visualAnchor = -1
+	}
+	e.setMode(mode)
+}
+
+func (e *VimEditor) setMode(mode VimMode) {
+	if mode == e.mode {
+		return
+	}
+	e.mode = mode
+	if e.OnModeChange != nil {
+		e.OnModeChange(mode)
+	}
+}
+
+func (e *VimEditor) resetPending() {
+	e.count = 0
+	e.operator = 0
+	e.operatorCount = 0
+	e.prefix = 0
+}
+
+func (e *VimEditor) inVisualMode() bool {
+	return e.mode == VimModeVisual || e.mode == VimModeVisualLine
+}
+
+// Edit implements Editor.
+func (e *VimEditor) Edit(v *View, key Key, ch rune, mod Modifier) bool {
+	if e.mode == VimModeInsert {
+		if key != KeyEsc || ch != 0 {
+			return SimpleEditor(v, key, ch, mod)
+		}
+		t := v.TextArea
+		if t.cursor > lineStartAt(t.content, t.cursor) {
+			t.cursor = prevGrapheme(t.content, t.cursor)
+		}
+		e.setMode(VimModeNormal)
+		v.RenderTextArea()
+		return true
+	}
+
+	if !e.editNormal(v.TextArea, key, ch, mod) {
+		return false
+	}
+	v.RenderTextArea()
+	return true
+}
+
+// editNormal handles a key in normal or visual mode, returning false if the
+// key isn't used by the editor.
+func (e *VimEditor) editNormal(t *TextArea, key Key, ch rune, mod Modifier) bool {
+	if mod != ModNone {
+		return false
+	}
+	if e.inVisualMode() && e.visualAnchor < 0 {
+		e.visualAnchor = t.cursor
+	}
+
+	if ch == 0 {
+		switch key {
+		case KeyArrowLeft:
+			ch = 'h'
+		case KeyArrowRight:
+			ch = 'l'
+		case KeyArrowUp:
+			ch = 'k'
+		case KeyArrowDown:
+			ch = 'j'
+		case KeyHome:
+			ch = '0'
+		case KeyEnd:
+			ch = '$'
+		case KeyEsc:
+			if e.count == 0 && e.operator == 0 && e.prefix == 0 && !e.inVisualMode() {
+				return false
+			}
+			e.resetPending()
+			e.setMode(VimModeNormal)
+			return true
+		default:
+			e.resetPending()
+			return false
+		}
+	}
+
+	if e.prefix != 0 {
+		prefix := e.prefix
+		e.prefix = 0
+		switch {
+		case prefix == 'g' && ch == 'g':
+			e.motion(t, ch)
+		case prefix == 'i' || prefix == 'a':
+			e.textObject(t, prefix, ch)
+		default:
+			e.resetPending()
+		}
+		return true
+	}
+
+	if ch >= '1' && ch <= '9' || ch == '0' && e.count > 0 {
+		e.count = e.count*10 + int(ch-'0')
+		return true
+	}
+
+	switch ch {
+	case 'g':
+		e.prefix = ch
+		return true
+	case 'h', 'j', 'k', 'l', 'w', 'b', 'e', '0', '^', '$', 'G':
+		e.motion(t, ch)
+		return true
+	case 'i', 'a':
+		if e.operator != 0 || e.inVisualMode() {
+			e.prefix = ch
+			return true
+		}
+	}
+
+	if e.inVisualMode() {
+		e.visualCommand(t, ch)
+		return true
+	}
+
+	switch ch {
+	case 'd', 'c', 'y':
+		switch e.operator {
+		case 0:
+			e.operator = ch
+			e.operatorCount = e.count
+			e.count = 0
+		case ch:
+			// dd, cc and yy act on count lines
+			count := e.takeCount()
+			e.resetPending()
+			end := t.cursor
+			for range count - 1 {
+				end = lineDown(t.content, end, 1)
+			}
+			e.operate(t, ch, t.cursor, end, motionLinewise)
+		default:
+			e.resetPending()
+		}
+		return true
+	}
+
+	if e.operator != 0 {
+		e.resetPending()
+		return true
+	}
+
+	count := e.takeCount()
+	s := t.content
+	switch ch {
+	case 'x', 's':
+		end := t.cursor
+		for range count {
+			end = min(nextGrapheme(s, end), lineEndAt(s, t.cursor))
+		}
+		op := 'd'
+		if ch == 's' {
+			op = 'c'
+		}
+		e.operate(t, op, t.cursor, end, motionExclusive)
+	case 'X':
+		start := t.cursor
+		for range count {
+			start = max(prevGrapheme(s, start), lineStartAt(s, t.cursor))
+		}
+		e.operate(t, 'd', start, t.cursor, motionExclusive)
+	case 'D', 'C':
+		op := 'd'
+		if ch == 'C' {
+			op = 'c'
+		}
+		e.operate(t, op, t.cursor, lineEndAt(s, t.cursor), motionExclusive)
+	case 'S', 'Y':
+		op := 'c'
+		if ch == 'Y' {
+			op = 'y'
+		}
+		end := t.cursor
+		for range count - 1 {
+			end = lineDown(s, end, 1)
+		}
+		e.operate(t, op, t.cursor, end, motionLinewise)
+	case 'p', 'P':
+		e.put(t, ch == 'p', count)
+	case 'I':
+		t.cursor = firstNonBlank(s, t.cursor)
+		e.setMode(VimModeInsert)
+	case 'i':
+		e.setMode(VimModeInsert)
+	case 'A':
+		t.cursor = lineEndAt(s, t.cursor)
+		e.setMode(VimModeInsert)
+	case 'a':
+		t.cursor = min(nextGrapheme(s, t.cursor), lineEndAt(s, t.cursor))
+		e.setMode(VimModeInsert)
+	case 'o':
+		t.cursor = lineEndAt(s, t.cursor)
+		t.TypeCharacter("\n")
+		e.setMode(VimModeInsert)
+	case 'O':
+		lineStart := lineStartAt(s, t.cursor)
+		t.cursor = lineStart
+		t.TypeCharacter("\n")
+		t.cursor = min(lineStart, len(t.content))
+		e.setMode(VimModeInsert)
+	case 'v':
+		e.visualAnchor = t.cursor
+		e.setMode(VimModeVisual)
+	case 'V':
+		e.visualAnchor = t.cursor
+		e.setMode(VimModeVisualLine)
+	}
+	return true
+}
+
+// visualCommand handles a command (other than a motion) in visual mode.
+func (e *VimEditor) visualCommand(t *TextArea, ch rune) {
+	e.resetPending()
+	switch ch {
+	case 'd', 'x', 'c', 'y':
+		op := ch
+		if op == 'x' {
+			op = 'd'
+		}
+		kind := motionInclusive
+		if e.mode == VimModeVisualLine {
+			kind = motionLinewise
+		}
+		anchor := min(e.visualAnchor, len(t.content))
+		e.setMode(VimModeNormal)
+		e.operate(t, op, anchor, t.cursor, kind)
+	case 'o':
+		e.visualAnchor, t.cursor = t.cursor, min(e.visualAnchor, len(t.content))
+	case 'v', 'V':
+		mode := VimModeVisual
+		if ch == 'V' {
+			mode = VimModeVisualLine
+		}
+		if e.mode == mode {
+			mode = VimModeNormal
+		}
+		e.setMode(mode)
+	}
+}
+
+// takeCount returns the count of the pending command (at least 1), and
+// resets it.
+func (e *VimEditor) takeCount() int {
+	count := max(e.count, 1) * max(e.operatorCount, 1)
+	e.count = 0
+	e.operatorCount = 0
+	return count
+}
+
+// motion moves the cursor, or applies the pending operator to the text that
+// the motion covers.
+func (e *VimEditor) motion(t *TextArea, ch rune) {
+	hasCount := e.count > 0 || e.operatorCount > 0
+	count := e.takeCount()
+	s := t.content
+	cursor := t.cursor
+	target := cursor
+	kind := motionExclusive
+
+	switch ch {
+	case 'h':
+		for range count {
+			target = max(prevGrapheme(s, target), lineStartAt(s, cursor))
+		}
+	case 'l':
+		for range count {
+			target = min(nextGrapheme(s, target), lineEndAt(s, cursor))
+		}
+	case 'j', 'k':
+		direction := 1
+		if ch == 'k' {
+			direction = -1
+		}
+		target = lineDown(s, cursor, direction*count)
+		kind = motionLinewise
+	case 'w':
+		if e.operator == 'c' && cursor < len(s) && charClass(s, cursor) != classSpace {
+			// like in vim, cw changes up to the end of the word
+			for range count {
+				target = wordEnd(s, target, true)
+			}
+			kind = motionInclusive
+			break
+		}
+		for range count {
+			target = wordForward(s, target)
+		}
+		if e.operator != 0 {
+			// the operator doesn't extend to the start of the word on the
+			// next line
+			if i := strings.LastIndexByte(s[cursor:target], '\n'); i >= 0 && strings.TrimLeft(s[cursor+i:target], WHITESPACES+"\n") == "" {
+				target = cursor + i
+			}
+		}
+	case 'b':
+		for range count {
+			target = wordBackward(s, target)
+		}
+	case 'e':
+		for range count {
+			target = wordEnd(s, target, false)
+		}
+		kind = motionInclusive
+	case '0':
+		target = lineStartAt(s, cursor)
+	case '^':
+		target = firstNonBlank(s, cursor)
+	case '$':
+		target = lineDown(s, cursor, count-1)
+		target = max(prevGrapheme(s, lineEndAt(s, target)), lineStartAt(s, target))
+		kind = motionInclusive
+		if e.operator != 0 {
+			target = lineEndAt(s, target)
+			kind = motionExclusive
+		}
+	case 'g', 'G':
+		line := 0
+		if ch == 'G' {
+			line = strings.Count(s, "\n")
+		}
+		if hasCount {
+			line = count - 1
+		}
+		target = firstNonBlank(s, lineDown(s, 0, line))
+		kind = motionLinewise
+	}
+
+	if e.operator != 0 {
+		op := e.operator
+		e.resetPending()
+		e.operate(t, op, cursor, target, kind)
+		return
+	}
+
+	t.cursor = target
+	e.clampCursor(t)
+}
+
+// textObject applies the pending operator to a text object, or selects it in
+// visual mode. The prefix is 'i' for the inner object or 'a' for the object
+// including surrounding whitespace.
+func (e *VimEditor) textObject(t *TextArea, prefix rune, ch rune) {
+	count := e.takeCount()
+	s := t.content
+	var start, end int
+	kind := motionExclusive
+
+	switch ch {
+	case 'w':
+		start, end = wordObject(s, t.cursor, count, prefix == 'a')
+	case 'l':
+		start = lineStartAt(s, t.cursor)
+		end = lineEndAt(s, lineDown(s, t.cursor, count-1))
+		if prefix == 'a' {
+			kind = motionLinewise
+		} else {
+			start = firstNonBlank(s, start)
+			end = max(start, len(strings.TrimRight(s[:end], WHITESPACES)))
+		}
+	default:
+		e.resetPending()
+		return
+	}
+
+	if e.inVisualMode() {
+		if kind == motionLinewise {
+			e.setMode(VimModeVisualLine)
+		}
+		e.visualAnchor = start
+		t.cursor = max(start, prevGrapheme(s, end))
+		return
+	}
+
+	op := e.operator
+	e.resetPending()
+	if kind == motionLinewise {
+		e.operate(t, op, start, end, kind)
+	} else if end > start {
+		e.operate(t, op, start, end, motionExclusive)
+	}
+}
+
+// operate applies the operator ('d', 'c' or 'y') to the text between the two
+// positions, as determined by the kind of motion.
+func (e *VimEditor) operate(t *TextArea, op rune, from, to int, kind motionKind) {
+	s := t.content
+	start, end := min(from, to), max(from, to)
+	// where the cursor goes when yanking
+	yankCursor := start
+
+	if kind == motionLinewise {
+		start = lineStartAt(s, start)
+		yankCursor = min(t.cursor, start)
+		if start == lineStartAt(s, t.cursor) {
+			yankCursor = t.cursor
+		}
+		lineEnd := lineEndAt(s, end)
+		e.register = s[start:lineEnd] + "\n"
+		e.registerLinewise = true
+
+		end = lineEnd
+		switch {
+		case op == 'c':
+			// keep an empty line to type into
+		case end < len(s):
+			end++
+		case start > 0:
+			start--
+		}
+	} else {
+		if kind == motionInclusive && end < len(s) {
+			end = nextGrapheme(s, end)
+		}
+		e.register = s[start:end]
+		e.registerLinewise = false
+	}
+
+	switch op {
+	case 'y':
+		t.cursor = yankCursor
+	case 'd', 'c':
+		t.content = s[:start] + s[end:]
+		t.cursor = min(start, len(t.content))
+		if kind == motionLinewise && op == 'd' {
+			t.cursor = firstNonBlank(t.content, t.cursor)
+		}
+		t.updateCells()
+	}
+
+	if op == 'c' {
+		e.setMode(VimModeInsert)
+		return
+	}
+	e.clampCursor(t)
+}
+
+// put inserts the register's content count times after (or before) the
+// cursor, or below (or above) the cursor's line if it holds whole lines.
+func (e *VimEditor) put(t *TextArea, after bool, count int) {
+	if e.register == "" {
+		return
+	}
+	text := strings.Repeat(e.register, count)
+
+	if e.registerLinewise {
+		lineStart := lineStartAt(t.content, t.cursor)
+		if after {
+			t.cursor = lineEndAt(t.content, t.cursor)
+			t.TypeString("\n" + strings.TrimSuffix(text, "\n"))
+			lineStart = lineStartAt(t.content, t.cursor)
+			// the first of the new lines
+			lineStart = lineDown(t.content, lineStart, -(strings.Count(text, "\n") - 1))
+		} else {
+			t.cursor = lineStart
+			t.TypeString(text)
+		}
+		t.cursor = firstNonBlank(t.content, min(lineStart, len(t.content)))
+		return
+	}
+
+	if after {
+		t.cursor = min(nextGrapheme(t.content, t.cursor), lineEndAt(t.content, t.cursor))
+	}
+	t.TypeString(text)
+	t.cursor = prevGrapheme(t.content, t.cursor)
+	e.clampCursor(t)
+}
+
+// clampCursor keeps the cursor on a character, as in vim's normal mode it
+// can't be placed after the end of a line.
+func (e *VimEditor) clampCursor(t *TextArea) {
+	s := t.content
+	t.cursor = min(t.cursor, len(s))
+	if t.cursor == lineEndAt(s, t.cursor) && t.cursor > lineStartAt(s, t.cursor) {
+		t.cursor = prevGrapheme(s, t.cursor)
+	}
+}
+
+// textAreaSelection returns the range of the visual mode selection, if any.
+func (e *VimEditor) textAreaSelection(t *TextArea) (int, int, bool) {
+	if !e.inVisualMode() {
+		return 0, 0, false
+	}
+
+	s := t.content
+	anchor := t.cursor
+	if e.visualAnchor >= 0 {
+		anchor = min(e.visualAnchor, len(s))
+	}
+	start, end := min(anchor, t.cursor), max(anchor, t.cursor)
+	if e.mode == VimModeVisualLine {
+		return lineStartAt(s, start), lineEndAt(s, end), true
+	}
+	return start, nextGrapheme(s, end), true
+}
+
+func lineStartAt(s string, pos int) int {
+	return strings.LastIndexByte(s[:pos], '\n') + 1
+}
+
+func lineEndAt(s string, pos int) int {
+	if i := strings.IndexByte(s[pos:], '\n'); i >= 0 {
+		return pos + i
+	}
+	return len(s)
+}
+
+func firstNonBlank(s string, pos int) int {
+	pos = lineStartAt(s, pos)
+	for pos < len(s) && strings.IndexByte(WHITESPACES, s[pos]) >= 0 {
+		pos++
+	}
+	return pos
+}
+
+func nextGrapheme(s string, pos int) int {
+	if pos >= len(s) {
+		return len(s)
+	}
+	cluster, _, _, _ := uniseg.FirstGraphemeClusterInString(s[pos:], -1)
+	return pos + len(cluster)
+}
+
+func prevGrapheme(s string, pos int) int {
+	lineStart := lineStartAt(s, pos)
+	if pos == lineStart {
+		return max(pos-1, 0)
+	}
+
+	prev := lineStart
+	for i := lineStart; i < pos; i = nextGrapheme(s, i) {
+		prev = i
+	}
+	return prev
+}
+
+// lineDown returns the position in the line that is the given number of
+// lines below (or above, if negative) the line at pos, in the same column as
+// far as possible. It stops at the first or last line.
+func lineDown(s string, pos int, lines int) int {
+	lineStart := lineStartAt(s, pos)
+	column := 0
+	for i := lineStart; i < pos; i = nextGrapheme(s, i) {
+		column++
+	}
+
+	for ; lines > 0; lines-- {
+		lineEnd := lineEndAt(s, lineStart)
+		if lineEnd == len(s) {
+			break
+		}
+		lineStart = lineEnd + 1
+	}
+	for ; lines < 0 && lineStart > 0; lines++ {
+		lineStart = lineStartAt(s, lineStart-1)
+	}
+
+	pos = lineStart
+	lineEnd := lineEndAt(s, lineStart)
+	for ; column > 0 && pos < lineEnd; column-- {
+		pos = nextGrapheme(s, pos)
+	}
+	return pos
+}
+
+// the classes of characters that make up vim's words
+const (
+	classSpace = iota
+	classWord
+	classPunctuation
+)
+
+func charClass(s string, pos int) int {
+	r, _ := utf8.DecodeRuneInString(s[pos:])
+	switch {
+	case unicode.IsSpace(r):
+		return classSpace
+	case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+		return classWord
+	default:
+		return classPunctuation
+	}
+}
+
+// wordForward returns the start of the next word.
+func wordForward(s string, pos int) int {
+	if pos < len(s) {
+		if class := charClass(s, pos); class != classSpace {
+			for pos < len(s) && charClass(s, pos) == class {
+				pos = nextGrapheme(s, pos)
+			}
+		}
+	}
+	for pos < len(s) && charClass(s, pos) == classSpace {
+		pos = nextGrapheme(s, pos)
+	}
+	return pos
+}
+
+// wordBackward returns the start of the word before pos, or of the word that
+// pos is in.
+func wordBackward(s string, pos int) int {
+	pos = prevGrapheme(s, pos)
+	for pos > 0 && charClass(s, pos) == classSpace {
+		pos = prevGrapheme(s, pos)
+	}
+	if pos >= len(s) {
+		return pos
+	}
+	class := charClass(s, pos)
+	for pos > 0 {
+		prev := prevGrapheme(s, pos)
+		if charClass(s, prev) != class {
+			break
+		}
+		pos = prev
+	}
+	return pos
+}
+
+// wordEnd returns the position of the last character of the next word, or of
+// the word that pos is in if stay is true and pos isn't at its end already.
+func wordEnd(s string, pos int, stay bool) int {
+	if !stay {
+		pos = nextGrapheme(s, pos)
+	}
+	for pos < len(s) && charClass(s, pos) == classSpace {
+		pos = nextGrapheme(s, pos)
+	}
+	if pos >= len(s) {
+		return prevGrapheme(s, len(s))
+	}
+	class := charClass(s, pos)
+	for {
+		next := nextGrapheme(s, pos)
+		if next >= len(s) || charClass(s, next) != class {
+			return pos
+		}
+		pos = next
+	}
+}
+
+// wordObject returns the range of count words (runs of characters of the same
+// class) starting with the one at pos, within its line. If around is true,
+// the whitespace after the words is included, or the whitespace before them
+// if there is none after.
+func wordObject(s string, pos int, count int, around bool) (int, int) {
+	lineStart, lineEnd := lineStartAt(s, pos), lineEndAt(s, pos)
+	if pos == lineEnd {
+		if pos == lineStart {
+			return pos, pos
+		}
+		pos = prevGrapheme(s, pos)
+	}
+
+	start := pos
+	class := charClass(s, pos)
+	for start > lineStart && charClass(s, prevGrapheme(s, start)) == class {
+		start = prevGrapheme(s, start)
+	}
+
+	extend := func(end int) int {
+		class := charClass(s, end)
+		for end < lineEnd && charClass(s, end) == class {
+			end = nextGrapheme(s, end)
+		}
+		return end
+	}
+
+	end := start
+	for range count {
+		if end >= lineEnd {
+			break
+		}
+		end = extend(end)
+		if around && end < lineEnd && (charClass(s, end) == classSpace) != (class == classSpace) {
+			end = extend(end)
+		}
+	}
+
+	if around && class != classSpace && (end == lineEnd || charClass(s, prevGrapheme(s, end)) != classSpace) {
+		for start > lineStart && charClass(s, prevGrapheme(s, start)) == classSpace {
+			start = prevGrapheme(s, start)
+		}
+	}
+	return start, end
+}
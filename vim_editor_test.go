@@ -0,0 +1,177 @@
+package gocui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// typeVimKeys sends the given keys to the editor; '\x1b' stands for Esc.
+func typeVimKeys(e *VimEditor, v *View, keys string) {
+	for _, ch := range keys {
+		switch ch {
+		case '\x1b':
+			e.Edit(v, KeyEsc, 0, ModNone)
+		case ' ':
+			e.Edit(v, KeySpace, 0, ModNone)
+		default:
+			e.Edit(v, 0, ch, ModNone)
+		}
+	}
+}
+
+func TestVimEditor(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		cursor       int
+		keys         string
+		expected     string
+		expectedCur  int
+		expectedMode VimMode
+	}{
+		{name: "h stops at line start", content: "ab\ncd", cursor: 4, keys: "hh", expected: "ab\ncd", expectedCur: 3},
+		{name: "l stops at last char", content: "ab\ncd", cursor: 0, keys: "5l", expected: "ab\ncd", expectedCur: 1},
+		{name: "j keeps column", content: "abc\nd\nefg", cursor: 2, keys: "j", expected: "abc\nd\nefg", expectedCur: 4},
+		{name: "2j", content: "abc\nd\nefg", cursor: 2, keys: "2j", expected: "abc\nd\nefg", expectedCur: 8},
+		{name: "w", content: "foo.bar baz", cursor: 0, keys: "w", expected: "foo.bar baz", expectedCur: 3},
+		{name: "3w", content: "foo.bar baz", cursor: 0, keys: "3w", expected: "foo.bar baz", expectedCur: 8},
+		{name: "w across lines", content: "foo\n  bar", cursor: 0, keys: "w", expected: "foo\n  bar", expectedCur: 6},
+		{name: "b", content: "foo bar", cursor: 5, keys: "b", expected: "foo bar", expectedCur: 4},
+		{name: "2b", content: "foo bar", cursor: 5, keys: "2b", expected: "foo bar", expectedCur: 0},
+		{name: "e", content: "foo bar", cursor: 0, keys: "e", expected: "foo bar", expectedCur: 2},
+		{name: "ee", content: "foo bar", cursor: 0, keys: "ee", expected: "foo bar", expectedCur: 6},
+		{name: "0 and $", content: "abc\ndef", cursor: 5, keys: "$", expected: "abc\ndef", expectedCur: 6},
+		{name: "0", content: "abc\ndef", cursor: 5, keys: "0", expected: "abc\ndef", expectedCur: 4},
+		{name: "G", content: "a\nb\n  c", cursor: 0, keys: "G", expected: "a\nb\n  c", expectedCur: 6},
+		{name: "gg", content: "  a\nb\nc", cursor: 6, keys: "gg", expected: "  a\nb\nc", expectedCur: 2},
+		{name: "2G", content: "a\nb\nc", cursor: 0, keys: "2G", expected: "a\nb\nc", expectedCur: 2},
+		{name: "multi-byte", content: "héllo", cursor: 0, keys: "ll", expected: "héllo", expectedCur: 3},
+
+		{name: "dw", content: "foo bar baz", cursor: 0, keys: "dw", expected: "bar baz", expectedCur: 0},
+		{name: "2dw", content: "foo bar baz", cursor: 0, keys: "2dw", expected: "baz", expectedCur: 0},
+		{name: "d2w", content: "foo bar baz", cursor: 0, keys: "d2w", expected: "baz", expectedCur: 0},
+		{name: "dw on last word", content: "foo bar\nbaz", cursor: 4, keys: "dw", expected: "foo \nbaz", expectedCur: 3},
+		{name: "de", content: "foo bar", cursor: 0, keys: "de", expected: " bar", expectedCur: 0},
+		{name: "db", content: "foo bar", cursor: 4, keys: "db", expected: "bar", expectedCur: 0},
+		{name: "d$", content: "foo bar\nbaz", cursor: 4, keys: "d$", expected: "foo \nbaz", expectedCur: 3},
+		{name: "D", content: "foo bar", cursor: 4, keys: "D", expected: "foo ", expectedCur: 3},
+		{name: "d0", content: "foo bar", cursor: 4, keys: "d0", expected: "bar", expectedCur: 0},
+		{name: "dd", content: "a\nb\nc", cursor: 2, keys: "dd", expected: "a\nc", expectedCur: 2},
+		{name: "dd last line", content: "a\nb\nc", cursor: 4, keys: "dd", expected: "a\nb", expectedCur: 2},
+		{name: "2dd", content: "a\nb\nc", cursor: 0, keys: "2dd", expected: "c", expectedCur: 0},
+		{name: "dj", content: "a\nb\nc", cursor: 2, keys: "dj", expected: "a", expectedCur: 0},
+		{name: "dG", content: "a\nb\nc", cursor: 2, keys: "dG", expected: "a", expectedCur: 0},
+		{name: "x", content: "abc", cursor: 1, keys: "x", expected: "ac", expectedCur: 1},
+		{name: "x at end", content: "abc", cursor: 2, keys: "x", expected: "ab", expectedCur: 1},
+		{name: "3x", content: "abcde", cursor: 1, keys: "3x", expected: "ae", expectedCur: 1},
+		{name: "X", content: "abc", cursor: 2, keys: "X", expected: "ac", expectedCur: 1},
+		{name: "invalid operator combination", content: "abc", cursor: 0, keys: "dyx", expected: "bc", expectedCur: 0},
+
+		{name: "cw", content: "foo bar", cursor: 0, keys: "cwx\x1b", expected: "x bar", expectedCur: 0},
+		{name: "cc", content: "a\nbc\nd", cursor: 3, keys: "ccx", expected: "a\nx\nd", expectedCur: 3, expectedMode: VimModeInsert},
+		{name: "C", content: "foo bar", cursor: 4, keys: "Cx", expected: "foo x", expectedCur: 5, expectedMode: VimModeInsert},
+		{name: "diw", content: "foo bar baz", cursor: 5, keys: "diw", expected: "foo  baz", expectedCur: 4},
+		{name: "daw", content: "foo bar baz", cursor: 5, keys: "daw", expected: "foo baz", expectedCur: 4},
+		{name: "daw on last word", content: "foo bar", cursor: 5, keys: "daw", expected: "foo", expectedCur: 2},
+		{name: "ciw", content: "foo.bar", cursor: 5, keys: "ciwx", expected: "foo.x", expectedCur: 5, expectedMode: VimModeInsert},
+		{name: "dil", content: "a\n  bc  \nd", cursor: 5, keys: "dil", expected: "a\n    \nd", expectedCur: 4},
+		{name: "dal", content: "a\n  bc  \nd", cursor: 5, keys: "dal", expected: "a\nd", expectedCur: 2},
+
+		{name: "yw and P", content: "foo bar", cursor: 4, keys: "ywP", expected: "foo barbar", expectedCur: 6},
+		{name: "yiw and p", content: "foo bar", cursor: 0, keys: "yiw$p", expected: "foo barfoo", expectedCur: 9},
+		{name: "yy and p", content: "a\nb", cursor: 0, keys: "yyjp", expected: "a\nb\na", expectedCur: 4},
+		{name: "yy and 2P", content: "a\nb", cursor: 2, keys: "yy2P", expected: "a\nb\nb\nb", expectedCur: 2},
+		{name: "dd and p", content: "a\nb\nc", cursor: 0, keys: "ddp", expected: "b\na\nc", expectedCur: 2},
+		{name: "x and p", content: "ab", cursor: 0, keys: "xp", expected: "ba", expectedCur: 1},
+
+		{name: "i", content: "abc", cursor: 1, keys: "ix", expected: "axbc", expectedCur: 2, expectedMode: VimModeInsert},
+		{name: "a", content: "abc", cursor: 1, keys: "ax", expected: "abxc", expectedCur: 3, expectedMode: VimModeInsert},
+		{name: "I", content: "  abc", cursor: 4, keys: "Ix", expected: "  xabc", expectedCur: 3, expectedMode: VimModeInsert},
+		{name: "A", content: "abc\nd", cursor: 0, keys: "Ax", expected: "abcx\nd", expectedCur: 4, expectedMode: VimModeInsert},
+		{name: "o", content: "abc\nd", cursor: 0, keys: "ox", expected: "abc\nx\nd", expectedCur: 5, expectedMode: VimModeInsert},
+		{name: "O", content: "abc\nd", cursor: 4, keys: "Ox", expected: "abc\nx\nd", expectedCur: 5, expectedMode: VimModeInsert},
+		{name: "esc from insert", content: "abc", cursor: 1, keys: "ix\x1b", expected: "axbc", expectedCur: 1},
+
+		{name: "visual delete", content: "foo bar", cursor: 1, keys: "vld", expected: "f bar", expectedCur: 1},
+		{name: "visual backwards", content: "foo bar", cursor: 4, keys: "vhd", expected: "fooar", expectedCur: 3},
+		{name: "visual change", content: "foo bar", cursor: 4, keys: "vecx", expected: "foo x", expectedCur: 5, expectedMode: VimModeInsert},
+		{name: "visual yank", content: "foo bar", cursor: 0, keys: "veyP", expected: "foofoo bar", expectedCur: 2},
+		{name: "visual line", content: "a\nb\nc", cursor: 0, keys: "Vjd", expected: "c", expectedCur: 0},
+		{name: "visual text object", content: "foo bar baz", cursor: 5, keys: "viwd", expected: "foo  baz", expectedCur: 4},
+		{name: "visual esc", content: "foo", cursor: 0, keys: "vl\x1b", expected: "foo", expectedCur: 1},
+		{name: "visual toggle", content: "foo", cursor: 0, keys: "vVv", expected: "foo", expectedCur: 0, expectedMode: VimModeVisual},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := NewView("v", 0, 0, 20, 10, OutputNormal)
+			v.Editable = true
+			e := &VimEditor{}
+			v.Editor = e
+			v.TextArea.TypeString(test.content)
+			v.TextArea.cursor = test.cursor
+
+			typeVimKeys(e, v, test.keys)
+
+			assert.Equal(t, test.expected, v.TextArea.GetUnwrappedContent())
+			assert.Equal(t, test.expectedCur, v.TextArea.GetCursor())
+			assert.Equal(t, test.expectedMode, e.Mode())
+		})
+	}
+}
+
+func TestVimEditorUnhandledKeys(t *testing.T) {
+	v := NewView("v", 0, 0, 20, 10, OutputNormal)
+	v.Editable = true
+	e := &VimEditor{}
+
+	assert.False(t, e.Edit(v, KeyEnter, 0, ModNone))
+	assert.False(t, e.Edit(v, KeyEsc, 0, ModNone))
+	assert.False(t, e.Edit(v, KeyCtrlS, 0, ModNone))
+
+	// Esc cancels a pending command
+	assert.True(t, e.Edit(v, 0, 'd', ModNone))
+	assert.True(t, e.Edit(v, KeyEsc, 0, ModNone))
+	assert.False(t, e.Edit(v, KeyEsc, 0, ModNone))
+
+	// in insert mode, keys are handled like in the simple editor
+	e.SetMode(VimModeInsert)
+	assert.True(t, e.Edit(v, KeyEnter, 0, ModNone))
+	assert.Equal(t, "\n", v.TextArea.GetUnwrappedContent())
+}
+
+func TestVimEditorModeChange(t *testing.T) {
+	v := NewView("v", 0, 0, 20, 10, OutputNormal)
+	v.Editable = true
+	var modes []VimMode
+	e := &VimEditor{OnModeChange: func(mode VimMode) { modes = append(modes, mode) }}
+
+	typeVimKeys(e, v, "ix\x1bvVy")
+	assert.Equal(t, []VimMode{VimModeInsert, VimModeNormal, VimModeVisual, VimModeVisualLine, VimModeNormal}, modes)
+	assert.Equal(t, "VISUAL LINE", VimModeVisualLine.String())
+}
+
+func TestVimEditorRendersSelection(t *testing.T) {
+	v := NewView("v", 0, 0, 20, 10, OutputNormal)
+	v.Editable = true
+	e := &VimEditor{}
+	v.Editor = e
+	v.TextArea.TypeString("foo bar")
+	v.TextArea.cursor = 0
+
+	typeVimKeys(e, v, "wve")
+
+	reversed := ""
+	for _, c := range v.lines[0] {
+		if c.fgColor&AttrReverse != 0 {
+			reversed += c.chr
+		}
+	}
+	assert.Equal(t, "bar", reversed)
+
+	typeVimKeys(e, v, "\x1b")
+	for _, c := range v.lines[0] {
+		assert.Zero(t, c.fgColor&AttrReverse)
+	}
+}